@@ -0,0 +1,82 @@
+package bybittest
+
+import (
+	"strconv"
+	"time"
+)
+
+// SpotBookTicker is a bookTicker push for PathPublicSpot.
+func SpotBookTicker(symbol, bidPrice, bidQty, askPrice, askQty string, ts time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"topic":  "bookTicker",
+		"params": map[string]interface{}{"symbol": symbol, "binary": "false"},
+		"data": map[string]interface{}{
+			"symbol":   symbol,
+			"bidPrice": bidPrice,
+			"bidQty":   bidQty,
+			"askPrice": askPrice,
+			"askQty":   askQty,
+			"time":     ts.UnixMilli(),
+		},
+	}
+}
+
+// SpotTrade is a public trade push for PathPublicSpot, buyerMaker means the taker sold.
+func SpotTrade(symbol, price, qty string, buyerMaker bool, ts time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"topic":  "trade",
+		"params": map[string]interface{}{"symbol": symbol, "binary": "false"},
+		"data": map[string]interface{}{
+			"v": strconv.FormatInt(ts.UnixNano(), 10),
+			"t": ts.UnixMilli(),
+			"p": price,
+			"q": qty,
+			"m": buyerMaker,
+		},
+	}
+}
+
+// PerpExecution is an execution push for PathPrivatePerp.
+func PerpExecution(symbol, side, orderID string, price, qty, fee float64, isMaker bool, ts time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"topic": "execution",
+		"data": []interface{}{
+			map[string]interface{}{
+				"symbol":     symbol,
+				"side":       side,
+				"order_id":   orderID,
+				"exec_id":    orderID + "-" + strconv.FormatInt(ts.UnixNano(), 10),
+				"price":      price,
+				"order_qty":  qty,
+				"exec_type":  "Trade",
+				"exec_qty":   qty,
+				"exec_fee":   fee,
+				"leaves_qty": 0,
+				"is_maker":   isMaker,
+				"trade_time": ts.UTC().Format("2006-01-02T15:04:05.999999Z"),
+			},
+		},
+	}
+}
+
+// SpotExecutionReport is an executionReport push for PathPrivateSpot,
+// status is FILLED / PARTIALLY_FILLED for fills, lastQty and lastPrice describe the fill itself.
+func SpotExecutionReport(symbol, side, orderID, orderType, status, lastPrice, lastQty, fee, feeAsset string, isMaker bool, ts time.Time) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"e": "executionReport",
+			"E": strconv.FormatInt(ts.UnixMilli(), 10),
+			"s": symbol,
+			"S": side,
+			"o": orderType,
+			"f": "GTC",
+			"X": status,
+			"i": orderID,
+			"l": lastQty,
+			"L": lastPrice,
+			"n": fee,
+			"N": feeAsset,
+			"m": isMaker,
+		},
+	}
+}
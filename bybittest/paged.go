@@ -0,0 +1,73 @@
+package bybittest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// PageCap is the last page bybit answers on the paged linear history lists.
+const PageCap = 50
+
+// PagedList answers a page / limit history list the way the linear endpoints do, records are
+// served in the given order, newest first like bybit. timeKey is the field holding each record's
+// timestamp in unit, start_time / end_time are read in the same unit. Pages past PageCap fail.
+//
+//	srv.Handle(http.MethodGet, "/private/linear/trade/execution/list", bybittest.PagedList(fills, "trade_time_ms", time.Millisecond))
+func PagedList(records []map[string]interface{}, timeKey string, unit time.Duration) HandlerFunc {
+	return func(r Request) (int, interface{}) {
+		page, err := strconv.Atoi(r.Param("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.Atoi(r.Param("limit"))
+		if err != nil || limit < 1 {
+			limit = 20
+		}
+		if page > PageCap {
+			return http.StatusOK, Fail(10001, "page out of range")
+		}
+		start, hasStart := paramTime(r, "start_time", unit)
+		end, hasEnd := paramTime(r, "end_time", unit)
+		var window []map[string]interface{}
+		for _, record := range records {
+			ts := recordTime(record[timeKey], unit)
+			if (hasStart && ts.Before(start)) || (hasEnd && ts.After(end)) {
+				continue
+			}
+			window = append(window, record)
+		}
+		from := (page - 1) * limit
+		if from > len(window) {
+			from = len(window)
+		}
+		to := from + limit
+		if to > len(window) {
+			to = len(window)
+		}
+		return http.StatusOK, OK(map[string]interface{}{"current_page": page, "data": window[from:to]})
+	}
+}
+
+func paramTime(r Request, key string, unit time.Duration) (time.Time, bool) {
+	n, err := strconv.ParseInt(r.Param(key), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, n*int64(unit)), true
+}
+
+func recordTime(v interface{}, unit time.Duration) time.Time {
+	var n int64
+	switch val := v.(type) {
+	case int:
+		n = int64(val)
+	case int64:
+		n = val
+	case float64:
+		n = int64(val)
+	case string:
+		n, _ = strconv.ParseInt(val, 10, 64)
+	}
+	return time.Unix(0, n*int64(unit))
+}
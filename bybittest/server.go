@@ -0,0 +1,402 @@
+// Package bybittest runs a local stand-in for the Bybit REST and websocket api,
+// so code built on bybitapi can be tested without network access.
+//
+//	srv := bybittest.NewServer("key", "secret")
+//	defer srv.Close()
+//	client := bybitapi.New("key", "secret", "", bybitapi.WithEnvironment(srv.Environment()))
+package bybittest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/gorilla/websocket"
+)
+
+// ret codes the server answers with when a request is rejected
+const (
+	CodeInvalidTimestamp = 10002
+	CodeInvalidAPIKey    = 10003
+	CodeInvalidSign      = 10004
+)
+
+const defaultRecvWindow = 5000
+
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+	// query and json body merged, values in their json form
+	Params map[string]string
+}
+
+func (r Request) Param(key string) string {
+	return r.Params[key]
+}
+
+// HandlerFunc scripts a response, body is marshalled to json unless it's already a string or []byte.
+type HandlerFunc func(r Request) (status int, body interface{})
+
+type Server struct {
+	URL    string
+	WSURL  string
+	Key    string
+	Secret string
+
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+
+	mux        sync.Mutex
+	routes     map[string]HandlerFunc
+	requests   []Request
+	timeOffset time.Duration
+	orderSeq   int
+	streams    map[string]*stream
+}
+
+func NewServer(key, secret string) *Server {
+	s := &Server{
+		Key:     key,
+		Secret:  secret,
+		routes:  make(map[string]HandlerFunc),
+		streams: make(map[string]*stream),
+	}
+	for _, spath := range streamPaths {
+		s.streams[spath] = newStream()
+	}
+	s.defaultRoutes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	s.WSURL = "ws" + strings.TrimPrefix(s.srv.URL, "http")
+	return s
+}
+
+func (s *Server) Close() {
+	for _, st := range s.streams {
+		st.closeAll()
+	}
+	s.srv.CloseClientConnections()
+	s.srv.Close()
+}
+
+func (s *Server) Environment() bybitapi.Environment {
+	return bybitapi.CustomEnvironment(s.URL, s.WSURL)
+}
+
+// Handle replaces the handler of method + path, ex: Handle(http.MethodPost, "/private/linear/order/create", h)
+func (s *Server) Handle(method, spath string, h HandlerFunc) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.routes[method+" "+spath] = h
+}
+
+// Respond always answers method + path with status 200 and body.
+func (s *Server) Respond(method, spath string, body interface{}) {
+	s.Handle(method, spath, func(Request) (int, interface{}) {
+		return http.StatusOK, body
+	})
+}
+
+// RespondError answers method + path with a bybit style error.
+func (s *Server) RespondError(method, spath string, retCode int, retMsg string) {
+	s.Handle(method, spath, func(Request) (int, interface{}) {
+		return http.StatusOK, Fail(retCode, retMsg)
+	})
+}
+
+// SetTimeOffset moves the server clock, for testing clock drift.
+func (s *Server) SetTimeOffset(offset time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.timeOffset = offset
+}
+
+func (s *Server) now() time.Time {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return time.Now().Add(s.timeOffset)
+}
+
+// Requests returns every request received so far, rejected ones included.
+func (s *Server) Requests() []Request {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	out := make([]Request, len(s.requests))
+	copy(out, s.requests)
+	return out
+}
+
+func (s *Server) LastRequest(method, spath string) (Request, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Method == method && s.requests[i].Path == spath {
+			return s.requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+// OK wraps result in a successful envelope, time_now in seconds as a decimal string like the v2 / linear api.
+// The /asset/v1 endpoints want OKAsset instead.
+func OK(result interface{}) map[string]interface{} {
+	return envelope(0, "OK", result, fmt.Sprintf("%.6f", float64(time.Now().UnixMicro())/1e6))
+}
+
+func Fail(retCode int, retMsg string) map[string]interface{} {
+	return envelope(retCode, retMsg, nil, fmt.Sprintf("%.6f", float64(time.Now().UnixMicro())/1e6))
+}
+
+// OKAsset is OK with time_now as integer milliseconds, the way the /asset/v1 endpoints send it.
+func OKAsset(result interface{}) map[string]interface{} {
+	return envelope(0, "OK", result, time.Now().UnixMilli())
+}
+
+func FailAsset(retCode int, retMsg string) map[string]interface{} {
+	return envelope(retCode, retMsg, nil, time.Now().UnixMilli())
+}
+
+func envelope(retCode int, retMsg string, result, timeNow interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"ret_code": retCode,
+		"ret_msg":  retMsg,
+		"ext_code": "",
+		"ext_info": "",
+		"result":   result,
+		"time_now": timeNow,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveStream(w, r)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Body:   body,
+		Params: make(map[string]string),
+	}
+	for k := range req.Query {
+		req.Params[k] = req.Query.Get(k)
+	}
+	bodyParams := make(map[string]interface{})
+	if len(body) != 0 {
+		if err := json.Unmarshal(body, &bodyParams); err == nil {
+			for k, v := range bodyParams {
+				req.Params[k] = jsonString(v)
+			}
+		}
+	}
+	s.mux.Lock()
+	s.requests = append(s.requests, req)
+	h, ok := s.routes[r.Method+" "+r.URL.Path]
+	s.mux.Unlock()
+
+	if !ok {
+		http.Error(w, "bybittest: no handler for "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	if code, msg := s.verify(req, bodyParams); code != 0 {
+		writeJSON(w, http.StatusOK, rejection(req.Path, code, msg))
+		return
+	}
+	status, out := h(req)
+	writeJSON(w, status, out)
+}
+
+// the error envelope in the form of the endpoint family
+func rejection(spath string, code int, msg string) map[string]interface{} {
+	if strings.HasPrefix(spath, "/asset/") {
+		return FailAsset(code, msg)
+	}
+	return Fail(code, msg)
+}
+
+// checks the query signature, and the body signature of endpoints which sign their json body
+func (s *Server) verify(req Request, bodyParams map[string]interface{}) (code int, msg string) {
+	if req.Query.Get("api_key") == "" {
+		return 0, ""
+	}
+	q := url.Values{}
+	for k, v := range req.Query {
+		q[k] = v
+	}
+	if code, msg := s.verifySigned(q); code != 0 {
+		return code, msg
+	}
+	if _, ok := bodyParams["sign"]; ok {
+		q := url.Values{}
+		for k, v := range bodyParams {
+			q.Set(k, jsonString(v))
+		}
+		return s.verifySigned(q)
+	}
+	return 0, ""
+}
+
+func (s *Server) verifySigned(q url.Values) (code int, msg string) {
+	if q.Get("api_key") != s.Key {
+		return CodeInvalidAPIKey, "invalid api_key"
+	}
+	sign := q.Get("sign")
+	q.Del("sign")
+	if !hmac.Equal([]byte(sign), []byte(s.sign(q.Encode()))) {
+		return CodeInvalidSign, "error sign! origin_string[" + q.Encode() + "]"
+	}
+	ts, err := strconv.ParseInt(q.Get("timestamp"), 10, 64)
+	if err != nil {
+		return CodeInvalidTimestamp, "invalid timestamp"
+	}
+	window := int64(defaultRecvWindow)
+	for _, key := range []string{"recv_window", "recvWindow"} {
+		if w, err := strconv.ParseInt(q.Get(key), 10, 64); err == nil {
+			window = w
+		}
+	}
+	now := s.now().UnixMilli()
+	if ts > now+1000 || now-ts > window {
+		return CodeInvalidTimestamp, "invalid request, please check your timestamp and recv_window param"
+	}
+	return 0, ""
+}
+
+func (s *Server) sign(payload string) string {
+	h := hmac.New(sha256.New, []byte(s.Secret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// number and bool params keep their json spelling, ex: 0.5, true
+func jsonString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case nil:
+		return ""
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	var b []byte
+	switch v := body.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		b, _ = json.Marshal(v)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func (s *Server) nextOrderID() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.orderSeq++
+	return fmt.Sprintf("bybittest-%d", s.orderSeq)
+}
+
+func (s *Server) defaultRoutes() {
+	s.routes["GET /v2/public/time"] = func(Request) (int, interface{}) {
+		out := OK(map[string]interface{}{})
+		out["time_now"] = fmt.Sprintf("%.6f", float64(s.now().UnixMicro())/1e6)
+		return http.StatusOK, out
+	}
+	s.routes["GET /spot/v1/time"] = func(Request) (int, interface{}) {
+		return http.StatusOK, OK(map[string]interface{}{
+			"serverTime": s.now().UnixMilli(),
+		})
+	}
+	s.routes["GET /v2/public/tickers"] = func(r Request) (int, interface{}) {
+		symbol := r.Param("symbol")
+		if symbol == "" {
+			symbol = "BTCUSDT"
+		}
+		return http.StatusOK, OK([]map[string]interface{}{{
+			"symbol":     symbol,
+			"bid_price":  "20000",
+			"ask_price":  "20000.5",
+			"last_price": "20000.5",
+			"mark_price": "20000.25",
+		}})
+	}
+	s.routes["POST /private/linear/order/create"] = func(r Request) (int, interface{}) {
+		now := s.now().UTC().Format(time.RFC3339)
+		price, _ := strconv.ParseFloat(r.Param("price"), 64)
+		qty, _ := strconv.ParseFloat(r.Param("qty"), 64)
+		return http.StatusOK, OK(map[string]interface{}{
+			"order_id":         s.nextOrderID(),
+			"symbol":           r.Param("symbol"),
+			"side":             r.Param("side"),
+			"order_type":       r.Param("order_type"),
+			"price":            price,
+			"qty":              qty,
+			"time_in_force":    r.Param("time_in_force"),
+			"order_status":     "Created",
+			"reduce_only":      r.Param("reduce_only") == "true",
+			"close_on_trigger": r.Param("close_on_trigger") == "true",
+			"order_link_id":    r.Param("order_link_id"),
+			"created_time":     now,
+			"updated_time":     now,
+		})
+	}
+	s.routes["POST /private/linear/order/replace"] = func(r Request) (int, interface{}) {
+		if r.Param("order_id") == "" && r.Param("order_link_id") == "" {
+			return http.StatusOK, Fail(10001, "missing order_id or order_link_id")
+		}
+		id := r.Param("order_id")
+		if id == "" {
+			id = s.nextOrderID()
+		}
+		return http.StatusOK, OK(map[string]interface{}{"order_id": id})
+	}
+	s.routes["POST /private/linear/stop-order/replace"] = func(r Request) (int, interface{}) {
+		if r.Param("stop_order_id") == "" && r.Param("order_link_id") == "" {
+			return http.StatusOK, Fail(10001, "missing stop_order_id or order_link_id")
+		}
+		id := r.Param("stop_order_id")
+		if id == "" {
+			id = s.nextOrderID()
+		}
+		return http.StatusOK, OK(map[string]interface{}{"stop_order_id": id})
+	}
+	s.routes["POST /spot/v1/order"] = func(r Request) (int, interface{}) {
+		return http.StatusOK, OK(map[string]interface{}{
+			"accountId":    "1",
+			"symbol":       r.Param("symbol"),
+			"symbolName":   r.Param("symbol"),
+			"orderLinkId":  r.Param("orderLinkId"),
+			"orderId":      s.nextOrderID(),
+			"transactTime": strconv.FormatInt(s.now().UnixMilli(), 10),
+			"price":        r.Param("price"),
+			"origQty":      r.Param("qty"),
+			"executedQty":  "0",
+			"status":       "NEW",
+			"timeInForce":  r.Param("timeInForce"),
+			"type":         r.Param("type"),
+			"side":         r.Param("side"),
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package bybittest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+func newClient(srv *bybittest.Server, key, secret string) *bybitapi.Client {
+	return bybitapi.New(key, secret, "", bybitapi.WithEnvironment(srv.Environment()))
+}
+
+func TestSignedOrderRoundTrip(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+	client := newClient(srv, "key", "secret")
+
+	res, err := client.PerpSubmitOrder(bybitapi.PerpOrderRequest{
+		Symbol:    "btcusdt",
+		Side:      bybitapi.Buy,
+		OrderType: bybitapi.Limit,
+		Price:     decimal.NewFromInt(20000),
+		Qty:       decimal.NewFromFloat(0.01),
+	})
+	if err != nil {
+		t.Fatalf("submit order: %v", err)
+	}
+	if res.Result.OrderID == "" {
+		t.Fatal("no order id in the response")
+	}
+	req, ok := srv.LastRequest(http.MethodPost, "/private/linear/order/create")
+	if !ok {
+		t.Fatal("order create was not received")
+	}
+	if got := req.Param("symbol"); got != "BTCUSDT" {
+		t.Errorf("symbol = %q, want BTCUSDT", got)
+	}
+	if req.Param("sign") == "" {
+		t.Error("request is not signed")
+	}
+}
+
+func TestSignedRequestRejected(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+
+	_, err := newClient(srv, "key", "wrong").PerpPlaceOrder("BTCUSDT", bybitapi.Buy, bybitapi.Limit, decimal.NewFromInt(20000), decimal.NewFromFloat(0.01), false)
+	var apiErr *bybitapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetCode != bybittest.CodeInvalidSign {
+		t.Errorf("wrong secret: err = %v, want ret_code %d", err, bybittest.CodeInvalidSign)
+	}
+
+	_, err = newClient(srv, "other", "secret").PerpPlaceOrder("BTCUSDT", bybitapi.Buy, bybitapi.Limit, decimal.NewFromInt(20000), decimal.NewFromFloat(0.01), false)
+	if !errors.As(err, &apiErr) || apiErr.RetCode != bybittest.CodeInvalidAPIKey {
+		t.Errorf("wrong key: err = %v, want ret_code %d", err, bybittest.CodeInvalidAPIKey)
+	}
+}
+
+func TestClockDrift(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+	client := newClient(srv, "key", "secret")
+
+	srv.SetTimeOffset(30 * time.Second)
+	_, err := client.SpotPlaceOrder("BTCUSDT", bybitapi.Buy, bybitapi.SpotLimit, decimal.NewFromInt(20000), decimal.NewFromFloat(0.01))
+	if !bybitapi.IsInvalidTimestamp(err) {
+		t.Fatalf("err = %v, want an invalid timestamp error", err)
+	}
+	if _, err := client.SyncServerTime(); err != nil {
+		t.Fatalf("sync server time: %v", err)
+	}
+	if _, err := client.SpotPlaceOrder("BTCUSDT", bybitapi.Buy, bybitapi.SpotLimit, decimal.NewFromInt(20000), decimal.NewFromFloat(0.01)); err != nil {
+		t.Errorf("after sync: %v", err)
+	}
+}
+
+func TestAssetTimeNow(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+	client := newClient(srv, "key", "secret")
+
+	// scripted bodies go out as they are, OK's decimal string doesn't fit the asset responses
+	srv.Respond(http.MethodPost, "/asset/v1/private/transfer", bybittest.OK(map[string]interface{}{"transfer_id": "t1"}))
+	if _, err := client.CreateInternalTransferWithID("t1", bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(1)); err == nil {
+		t.Error("transfer with OK decoded, want a time_now type error")
+	}
+
+	srv.Respond(http.MethodPost, "/asset/v1/private/transfer", bybittest.OKAsset(map[string]interface{}{"transfer_id": "t2"}))
+	res, err := client.CreateInternalTransferWithID("t2", bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(1))
+	if err != nil {
+		t.Fatalf("transfer with OKAsset: %v", err)
+	}
+	if res.Result.TransferID != "t2" || res.TimeNow == 0 {
+		t.Errorf("transfer id = %q, time_now = %d", res.Result.TransferID, res.TimeNow)
+	}
+
+	// the server's own rejections follow the asset form
+	_, err = newClient(srv, "key", "wrong").CreateInternalTransferWithID("t3", bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(1))
+	var apiErr *bybitapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetCode != bybittest.CodeInvalidSign {
+		t.Errorf("wrong secret: err = %v, want ret_code %d", err, bybittest.CodeInvalidSign)
+	}
+}
+
+func TestPrivateSpotStream(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+	client := newClient(srv, "key", "secret")
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	client.InitSpotPrivateChannel(logger)
+	defer client.CloseSpotPrivateChannel()
+	// only returns once the connection passed the auth check
+	if err := srv.WaitForStream(bybittest.PathPrivateSpot, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	report := bybittest.SpotExecutionReport("BTCUSDT", "BUY", "1", "LIMIT", bybitapi.Filled, "20000", "0.01", "0.00001", "BTC", true, time.Now())
+	if err := srv.Push(bybittest.PathPrivateSpot, report); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		trades, err := client.ReadSpotUserTrade()
+		if err != nil {
+			time.Sleep(20 * time.Millisecond)
+			continue
+		}
+		if len(trades) != 1 || trades[0].Oid != "1" || !trades[0].Price.Equal(decimal.NewFromInt(20000)) || !trades[0].IsMaker {
+			t.Fatalf("trades = %+v", trades)
+		}
+		return
+	}
+	t.Fatal("pushed fill never reached the client")
+}
+
+func TestPrivateStreamBadKey(t *testing.T) {
+	srv := bybittest.NewServer("key", "secret")
+	defer srv.Close()
+	client := newClient(srv, "key", "wrong")
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	client.InitSpotPrivateChannel(logger)
+	defer client.CloseSpotPrivateChannel()
+	if err := srv.WaitForStream(bybittest.PathPrivateSpot, 500*time.Millisecond); err == nil {
+		t.Error("stream authenticated with a wrong secret")
+	}
+}
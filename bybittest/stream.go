@@ -0,0 +1,255 @@
+package bybittest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	PathPublicPerp  = "/realtime_public"
+	PathPublicSpot  = "/spot/quote/ws/v2"
+	PathPrivatePerp = "/realtime_private"
+	PathPrivateSpot = "/spot/ws"
+)
+
+var streamPaths = []string{PathPublicPerp, PathPublicSpot, PathPrivatePerp, PathPrivateSpot}
+
+type stream struct {
+	mux   sync.Mutex
+	conns map[*streamConn]struct{}
+	// closed and replaced every time a connection is authenticated or opened
+	ready chan struct{}
+}
+
+func newStream() *stream {
+	return &stream{
+		conns: make(map[*streamConn]struct{}),
+		ready: make(chan struct{}),
+	}
+}
+
+func (st *stream) add(c *streamConn) {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	st.conns[c] = struct{}{}
+}
+
+func (st *stream) remove(c *streamConn) {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	delete(st.conns, c)
+}
+
+func (st *stream) signalReady() {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	close(st.ready)
+	st.ready = make(chan struct{})
+}
+
+func (st *stream) readyCh() chan struct{} {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	return st.ready
+}
+
+func (st *stream) closeAll() {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	for c := range st.conns {
+		c.conn.Close()
+	}
+}
+
+type streamConn struct {
+	mux    sync.Mutex
+	conn   *websocket.Conn
+	authed bool
+}
+
+func (c *streamConn) write(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	st, ok := s.streams[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &streamConn{conn: conn}
+	st.add(c)
+	defer st.remove(c)
+	defer conn.Close()
+	private := r.URL.Path == PathPrivatePerp || r.URL.Path == PathPrivateSpot
+	if !private {
+		st.signalReady()
+	}
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.handleStreamMessage(st, c, r.URL.Path, msg)
+	}
+}
+
+func (s *Server) handleStreamMessage(st *stream, c *streamConn, spath string, msg []byte) {
+	var in map[string]interface{}
+	if err := json.Unmarshal(msg, &in); err != nil {
+		return
+	}
+	if ts, ok := in["ping"]; ok {
+		c.write(map[string]interface{}{"pong": ts})
+		return
+	}
+	if in["event"] == "sub" {
+		c.write(map[string]interface{}{
+			"topic":  in["topic"],
+			"event":  "sub",
+			"params": in["params"],
+			"code":   "0",
+			"msg":    "Success",
+		})
+		return
+	}
+	op, _ := in["op"].(string)
+	args, _ := in["args"].([]interface{})
+	switch op {
+	case "ping":
+		c.write(opReply(true, "pong", op, args))
+	case "auth":
+		ok := s.verifyStreamAuth(args)
+		switch spath {
+		case PathPrivateSpot:
+			if ok {
+				c.write(map[string]interface{}{"auth": "success", "userId": 1})
+			} else {
+				c.write(map[string]interface{}{"auth": "fail", "userId": 0})
+			}
+		default:
+			c.write(opReply(ok, "", op, args))
+		}
+		if ok {
+			c.mux.Lock()
+			c.authed = true
+			c.mux.Unlock()
+			st.signalReady()
+		}
+	case "subscribe":
+		c.write(opReply(true, "", op, args))
+	}
+}
+
+func opReply(success bool, retMsg, op string, args []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"success": success,
+		"ret_msg": retMsg,
+		"request": map[string]interface{}{
+			"op":   op,
+			"args": args,
+		},
+	}
+}
+
+// args: api key, expires, hex(hmac("GET/realtime" + expires))
+func (s *Server) verifyStreamAuth(args []interface{}) bool {
+	if len(args) != 3 {
+		return false
+	}
+	key, _ := args[0].(string)
+	expires := fmt.Sprint(args[1])
+	sign, _ := args[2].(string)
+	if key != s.Key {
+		return false
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || exp < s.now().UnixMilli() {
+		return false
+	}
+	h := hmac.New(sha256.New, []byte(s.Secret))
+	h.Write([]byte("GET/realtime" + expires))
+	return hmac.Equal([]byte(sign), []byte(hex.EncodeToString(h.Sum(nil))))
+}
+
+// WaitForStream blocks until a connection on spath is open, and authenticated for the private paths.
+func (s *Server) WaitForStream(spath string, timeout time.Duration) error {
+	st, ok := s.streams[spath]
+	if !ok {
+		return errors.New("bybittest: unknown stream path " + spath)
+	}
+	ready := st.readyCh()
+	if s.streamReady(st, spath) {
+		return nil
+	}
+	select {
+	case <-ready:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("bybittest: timeout waiting for " + spath)
+	}
+}
+
+func (s *Server) streamReady(st *stream, spath string) bool {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	for c := range st.conns {
+		if spath != PathPrivatePerp && spath != PathPrivateSpot {
+			return true
+		}
+		c.mux.Lock()
+		authed := c.authed
+		c.mux.Unlock()
+		if authed {
+			return true
+		}
+	}
+	return false
+}
+
+// Push sends msg to every connection on spath, private paths only reach authenticated ones.
+func (s *Server) Push(spath string, msg interface{}) error {
+	st, ok := s.streams[spath]
+	if !ok {
+		return errors.New("bybittest: unknown stream path " + spath)
+	}
+	st.mux.Lock()
+	conns := make([]*streamConn, 0, len(st.conns))
+	for c := range st.conns {
+		conns = append(conns, c)
+	}
+	st.mux.Unlock()
+	private := spath == PathPrivatePerp || spath == PathPrivateSpot
+	for _, c := range conns {
+		c.mux.Lock()
+		authed := c.authed
+		c.mux.Unlock()
+		if private && !authed {
+			continue
+		}
+		if err := c.write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,15 +2,16 @@ package bybitapi
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -22,20 +23,39 @@ type Client struct {
 	key, secret        string
 	subaccount         string
 	client             *http.Client
+	env                Environment
+	limiter            *rateLimiter
+	retry              *RetryPolicy
+	clock              *serverClock
+	recvWindow         time.Duration
+	batchConcurrency   int
+	keys               KeyRegistry
+	subMux             sync.Mutex
+	subClients         map[string]*Client
+	withdrawWhitelist  map[string]map[string]bool
 	spotPrivateChannel *spotPrivateChannelBranch
 	perpPrivateChannel *perpPrivateChannelBranch
 }
 
-func New(key, secret, subaccount string) *Client {
+func New(key, secret, subaccount string, opts ...ClientOption) *Client {
 	hc := &http.Client{
 		Timeout: 10 * time.Second,
 	}
-	return &Client{
+	c := &Client{
 		key:        key,
 		secret:     secret,
 		subaccount: subaccount,
 		client:     hc,
+		env:        Mainnet,
+		limiter:    newRateLimiter(),
+		clock:      new(serverClock),
+
+		batchConcurrency: defaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 func (p *Client) getSigned(param string) string {
@@ -45,26 +65,31 @@ func (p *Client) getSigned(param string) string {
 	return signature
 }
 
-func (p *Client) newRequest(product, method, spath string, body []byte, params *map[string]string, auth bool) (*http.Request, error) {
+func (p *Client) newRequest(ctx context.Context, product, method, spath string, body []byte, params *map[string]string, auth bool) (*http.Request, error) {
 	q := url.Values{}
 	if params != nil {
 		for k, v := range *params {
 			q.Add(k, v)
 		}
 	}
-	var timestamp int64
 	if auth {
-		timestamp = time.Now().UnixNano() / 1e6
+		timestamp := p.clock.now().UnixMilli()
 		q.Add("api_key", p.key)
-		q.Add("timestamp", strconv.Itoa(int(timestamp)))
+		q.Add("timestamp", strconv.FormatInt(timestamp, 10))
+		if window := p.recvWindowFor(ctx); window != "" {
+			switch product {
+			case ProductSpot:
+				q.Add("recvWindow", window)
+			default:
+				q.Add("recv_window", window)
+			}
+		}
 	}
-	host := HostHub(product)
-
-	url, err := p.sign(host, method, spath, &q, auth)
+	url, err := p.sign(p.env.RESTURL, method, spath, &q, auth)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(method, url, strings.NewReader(string(body)))
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(string(body)))
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +107,44 @@ func (p *Client) newRequest(product, method, spath string, body []byte, params *
 	return req, nil
 }
 
-func (c *Client) sendRequest(product, method, spath string, body []byte, params *map[string]string, auth bool) (*http.Response, error) {
-	req, err := c.newRequest(product, method, spath, body, params, auth)
+func (c *Client) sendRequest(ctx context.Context, product, method, spath string, body []byte, params *map[string]string, auth bool) (*http.Response, error) {
+	build := func() ([]byte, error) {
+		return body, nil
+	}
+	if c.retry == nil || !retryable(method, body, params) {
+		return c.doRequest(ctx, product, method, spath, build, params, auth)
+	}
+	return c.retry.do(ctx, method, spath, func() (*http.Response, error) {
+		return c.doRequest(ctx, product, method, spath, build, params, auth)
+	})
+}
+
+// for endpoints which want the signed params inside the json body, the body is signed
+// in doRequest after the limiter so every attempt carries a fresh timestamp
+func (c *Client) sendSignedBody(ctx context.Context, method, spath string, params map[string]string) (*http.Response, error) {
+	build := func() ([]byte, error) {
+		return c.signedBody(ctx, params)
+	}
+	if c.retry == nil || !retryable(method, nil, &params) {
+		return c.doRequest(ctx, ProductPerp, method, spath, build, nil, true)
+	}
+	return c.retry.do(ctx, method, spath, func() (*http.Response, error) {
+		return c.doRequest(ctx, ProductPerp, method, spath, build, nil, true)
+	})
+}
+
+// one attempt, signed again every time so the timestamp stays fresh
+func (c *Client) doRequest(ctx context.Context, product, method, spath string, build func() ([]byte, error), params *map[string]string, auth bool) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx, method, spath); err != nil {
+			return nil, err
+		}
+	}
+	body, err := build()
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, product, method, spath, body, params, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -91,18 +152,31 @@ func (c *Client) sendRequest(product, method, spath string, body []byte, params
 	if err != nil {
 		return nil, err
 	}
-	if res.StatusCode != 200 {
-		//c.Logger.Printf("status: %s", res.Status)
-		buf := new(bytes.Buffer)
-		buf.ReadFrom(res.Body)
-		s := buf.String()
-		return nil, fmt.Errorf("faild to get data. status: %s, with error: %s", res.Status, s)
+	raw, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// hand the body back for decode()
+	res.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	env := new(retEnvelope)
+	json.Unmarshal(raw, env)
+	env.normalize()
+	env.fillRateLimit(res.Header)
+	if c.limiter != nil {
+		c.limiter.update(method, spath, env, c.ServerTimeOffset())
+	}
+	if err := checkResponse(spath, res, env, raw); err != nil {
+		return nil, err
 	}
 	return res, nil
 }
 
+// HostHub reports the mainnet rest host.
+//
+// Deprecated: requests are sent to the Client's Environment.
 func HostHub(product string) (host string) {
-	host = "api.bybit.com"
+	host = strings.TrimPrefix(Mainnet.RESTURL, "https://")
 	return host
 }
 
@@ -116,10 +190,30 @@ func decode(res *http.Response, out interface{}) error {
 	return err
 }
 
-func (c *Client) sign(host, method, spath string, q *url.Values, auth bool) (string, error) {
+// params are left untouched, the signed copy is marshalled
+func (p *Client) signedBody(ctx context.Context, params map[string]string) ([]byte, error) {
+	q := url.Values{}
+	signed := make(map[string]string, len(params)+4)
+	for k, v := range params {
+		q.Add(k, v)
+		signed[k] = v
+	}
+	timestamp := strconv.FormatInt(p.clock.now().UnixMilli(), 10)
+	q.Add("api_key", p.key)
+	q.Add("timestamp", timestamp)
+	signed["api_key"] = p.key
+	signed["timestamp"] = timestamp
+	if window := p.recvWindowFor(ctx); window != "" {
+		q.Add("recv_window", window)
+		signed["recv_window"] = window
+	}
+	signed["sign"] = p.getSigned(q.Encode())
+	return json.Marshal(signed)
+}
+
+func (c *Client) sign(baseURL, method, spath string, q *url.Values, auth bool) (string, error) {
 	var buffer bytes.Buffer
-	buffer.WriteString("https://")
-	buffer.WriteString(host)
+	buffer.WriteString(baseURL)
 	buffer.WriteString(spath)
 	if (*q).Encode() == "" {
 		return buffer.String(), nil
@@ -161,7 +255,11 @@ type ApiInfoResponse struct {
 }
 
 func (p *Client) ApiInfo() (result *ApiInfoResponse, err error) {
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/v2/private/account/api-key", nil, nil, true)
+	return p.ApiInfoContext(context.Background())
+}
+
+func (p *Client) ApiInfoContext(ctx context.Context) (result *ApiInfoResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/private/account/api-key", nil, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,37 @@
+package bybitapi_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const pathInternalTransfer = "/asset/v1/private/transfer"
+
+// the body signature has to be made after the limiter wait, not before
+func TestSignedBodyFreshAfterRateLimitWait(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithRecvWindow(500*time.Millisecond))
+
+	srv.Handle(http.MethodPost, pathInternalTransfer, func(r bybittest.Request) (int, interface{}) {
+		out := bybittest.OKAsset(map[string]interface{}{"transfer_id": r.Param("transfer_id")})
+		// asset budget used up for the next second
+		out["rate_limit"] = 60
+		out["rate_limit_status"] = 0
+		out["rate_limit_reset_ms"] = time.Now().Add(time.Second).UnixMilli()
+		return http.StatusOK, out
+	})
+	if _, err := client.CreateInternalTransferWithID("t1", bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.CreateInternalTransferWithID("t2", bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(1)); err != nil {
+		t.Fatalf("after the limiter wait: %v", err)
+	}
+	if waited := time.Since(start); waited < 500*time.Millisecond {
+		t.Errorf("waited %v, the bucket was not exhausted", waited)
+	}
+}
@@ -0,0 +1,130 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// local clock plus the offset measured against bybit, used for every signature
+type serverClock struct {
+	// milliseconds, server minus local
+	offset int64
+}
+
+func (s *serverClock) now() time.Time {
+	return time.Now().Add(time.Duration(atomic.LoadInt64(&s.offset)) * time.Millisecond)
+}
+
+func (s *serverClock) set(offset time.Duration) {
+	atomic.StoreInt64(&s.offset, offset.Milliseconds())
+}
+
+// ServerTimeOffset is how far bybit's clock is ahead of the local one, as of the last sync.
+func (c *Client) ServerTimeOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.clock.offset)) * time.Millisecond
+}
+
+const clockSyncSamples = 3
+
+// SyncServerTime samples the spot time endpoint, falls back to the perp one,
+// and keeps the sample with the lowest round trip. The server time is compared
+// against the local midpoint of the request.
+func (c *Client) SyncServerTime() (offset time.Duration, err error) {
+	return c.SyncServerTimeContext(context.Background())
+}
+
+func (c *Client) SyncServerTimeContext(ctx context.Context) (offset time.Duration, err error) {
+	bestRTT := time.Duration(-1)
+	for i := 0; i < clockSyncSamples; i++ {
+		start := time.Now()
+		server, err := c.serverTime(ctx)
+		if err != nil {
+			if bestRTT < 0 && i == clockSyncSamples-1 {
+				return 0, err
+			}
+			continue
+		}
+		rtt := time.Since(start)
+		if bestRTT >= 0 && rtt >= bestRTT {
+			continue
+		}
+		bestRTT = rtt
+		offset = server.Sub(start.Add(rtt / 2))
+	}
+	if bestRTT < 0 {
+		return 0, errors.New("no server time sample")
+	}
+	c.clock.set(offset)
+	return offset, nil
+}
+
+func (c *Client) serverTime(ctx context.Context) (time.Time, error) {
+	spot, err := c.GetSpotServerTimeContext(ctx)
+	if err == nil {
+		return time.UnixMilli(spot.Result.Servertime), nil
+	}
+	perp, err := c.GetPerpServerTimeContext(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// seconds with microsecond decimals, ex: "1577444332.192859"
+	sec, err := decimal.NewFromString(perp.TimeNow)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMicro(sec.Shift(6).IntPart()), nil
+}
+
+// StartClockSync syncs once and re-syncs every interval until ctx is done, a failed round keeps the previous offset.
+// Nothing is started when the first sync fails, its error is returned.
+func (c *Client) StartClockSync(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("clock sync interval must be positive")
+	}
+	if _, err := c.SyncServerTimeContext(ctx); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.SyncServerTimeContext(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// default recv_window for every signed request, bybit uses 5s when it's not sent
+func WithRecvWindow(window time.Duration) ClientOption {
+	return func(c *Client) {
+		c.recvWindow = window
+	}
+}
+
+type recvWindowKey struct{}
+
+// ContextWithRecvWindow overrides the client's recv_window for requests made with the returned context.
+func ContextWithRecvWindow(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, recvWindowKey{}, window)
+}
+
+func (c *Client) recvWindowFor(ctx context.Context) string {
+	window := c.recvWindow
+	if w, ok := ctx.Value(recvWindowKey{}).(time.Duration); ok {
+		window = w
+	}
+	if window <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(window.Milliseconds(), 10)
+}
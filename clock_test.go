@@ -0,0 +1,42 @@
+package bybitapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+)
+
+func TestStartClockSync(t *testing.T) {
+	srv, client := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := client.StartClockSync(ctx, 0); err == nil {
+		t.Error("zero interval accepted")
+	}
+	srv.SetTimeOffset(30 * time.Second)
+	if err := client.StartClockSync(ctx, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if offset := client.ServerTimeOffset(); offset < 29*time.Second || offset > 31*time.Second {
+		t.Errorf("offset = %v, want about 30s", offset)
+	}
+}
+
+func TestStartClockSyncFirstRoundFails(t *testing.T) {
+	srv, client := newTestClient(t)
+	down := func(bybittest.Request) (int, interface{}) {
+		return http.StatusServiceUnavailable, "unavailable"
+	}
+	srv.Handle(http.MethodGet, "/spot/v1/time", down)
+	srv.Handle(http.MethodGet, "/v2/public/time", down)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := client.StartClockSync(ctx, time.Minute); err == nil {
+		t.Error("failed first sync returned no error")
+	}
+}
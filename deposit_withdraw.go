@@ -0,0 +1,445 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CoinChainInfo struct {
+	ChainType    string          `json:"chain_type"`
+	Confirmation string          `json:"confirmation"`
+	WithdrawFee  decimal.Decimal `json:"withdraw_fee"`
+	DepositMin   decimal.Decimal `json:"deposit_min"`
+	WithdrawMin  decimal.Decimal `json:"withdraw_min"`
+	Chain        string          `json:"chain"`
+	// "1" enabled, "0" suspended
+	ChainDeposit  string `json:"chain_deposit"`
+	ChainWithdraw string `json:"chain_withdraw"`
+}
+
+func (c *CoinChainInfo) DepositEnabled() bool {
+	return c.ChainDeposit == "1"
+}
+
+func (c *CoinChainInfo) WithdrawEnabled() bool {
+	return c.ChainWithdraw == "1"
+}
+
+type CoinInfo struct {
+	Name         string          `json:"name"`
+	Coin         string          `json:"coin"`
+	RemainAmount decimal.Decimal `json:"remain_amount"`
+	Chains       []CoinChainInfo `json:"chains"`
+}
+
+func (c *CoinInfo) Chain(chain string) (CoinChainInfo, bool) {
+	for _, info := range c.Chains {
+		if strings.EqualFold(info.Chain, chain) {
+			return info, true
+		}
+	}
+	return CoinChainInfo{}, false
+}
+
+type CoinInfoResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		Rows []CoinInfo `json:"rows"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// every coin when coin is empty
+func (p *Client) GetCoinInfo(coin string) (result *CoinInfoResponse, err error) {
+	return p.GetCoinInfoContext(context.Background(), coin)
+}
+
+func (p *Client) GetCoinInfoContext(ctx context.Context, coin string) (result *CoinInfoResponse, err error) {
+	params := make(map[string]string)
+	if coin != "" {
+		params["coin"] = strings.ToUpper(coin)
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/coin-info/query", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type DepositAddressResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		Coin   string `json:"coin"`
+		Chains []struct {
+			ChainType      string `json:"chain_type"`
+			AddressDeposit string `json:"address_deposit"`
+			TagDeposit     string `json:"tag_deposit"`
+			Chain          string `json:"chain"`
+		} `json:"chains"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) GetDepositAddress(coin string) (result *DepositAddressResponse, err error) {
+	return p.GetDepositAddressContext(context.Background(), coin)
+}
+
+func (p *Client) GetDepositAddressContext(ctx context.Context, coin string) (result *DepositAddressResponse, err error) {
+	params := make(map[string]string)
+	params["coin"] = strings.ToUpper(coin)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/deposit/address", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// cursor paged, feed the response's Cursor back with Direction "Next" for the following page
+type AssetRecordFilter struct {
+	Coin string
+	// withdrawals only
+	WithdrawID string
+	// 30 days at most between them
+	StartTime time.Time
+	EndTime   time.Time
+	// Prev / Next
+	Direction string
+	Cursor    string
+	// 20 when zero, max 50
+	Limit int
+}
+
+func (f *AssetRecordFilter) params() map[string]string {
+	params := make(map[string]string)
+	if f.Coin != "" {
+		params["coin"] = strings.ToUpper(f.Coin)
+	}
+	if f.WithdrawID != "" {
+		params["withdraw_id"] = f.WithdrawID
+	}
+	// seconds
+	if !f.StartTime.IsZero() {
+		params["start_time"] = strconv.FormatInt(f.StartTime.Unix(), 10)
+	}
+	if !f.EndTime.IsZero() {
+		params["end_time"] = strconv.FormatInt(f.EndTime.Unix(), 10)
+	}
+	if f.Direction != "" {
+		params["direction"] = f.Direction
+	}
+	if f.Cursor != "" {
+		params["cursor"] = f.Cursor
+	}
+	if f.Limit > 0 {
+		params["limit"] = strconv.Itoa(f.Limit)
+	}
+	return params
+}
+
+type DepositRecord struct {
+	Coin       string          `json:"coin"`
+	Chain      string          `json:"chain"`
+	Amount     decimal.Decimal `json:"amount"`
+	TxID       string          `json:"tx_id"`
+	Status     int             `json:"status"`
+	ToAddress  string          `json:"to_address"`
+	Tag        string          `json:"tag"`
+	DepositFee decimal.Decimal `json:"deposit_fee"`
+	// seconds
+	SuccessAt     string `json:"success_at"`
+	Confirmations string `json:"confirmations"`
+	TxIndex       string `json:"tx_index"`
+	BlockHash     string `json:"block_hash"`
+}
+
+type DepositRecordsResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		Rows   []DepositRecord `json:"rows"`
+		Cursor string          `json:"cursor"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) GetDepositRecords(filter AssetRecordFilter) (result *DepositRecordsResponse, err error) {
+	return p.GetDepositRecordsContext(context.Background(), filter)
+}
+
+func (p *Client) GetDepositRecordsContext(ctx context.Context, filter AssetRecordFilter) (result *DepositRecordsResponse, err error) {
+	if filter.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params()
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/deposit/record/query", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type WithdrawRecord struct {
+	WithdrawID  string          `json:"withdraw_id"`
+	Coin        string          `json:"coin"`
+	Chain       string          `json:"chain"`
+	Amount      decimal.Decimal `json:"amount"`
+	TxID        string          `json:"tx_id"`
+	Status      string          `json:"status"`
+	ToAddress   string          `json:"to_address"`
+	Tag         string          `json:"tag"`
+	WithdrawFee decimal.Decimal `json:"withdraw_fee"`
+	// seconds
+	CreateTime string `json:"create_time"`
+	UpdateTime string `json:"update_time"`
+}
+
+type WithdrawRecordsResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		Rows   []WithdrawRecord `json:"rows"`
+		Cursor string           `json:"cursor"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) GetWithdrawRecords(filter AssetRecordFilter) (result *WithdrawRecordsResponse, err error) {
+	return p.GetWithdrawRecordsContext(context.Background(), filter)
+}
+
+func (p *Client) GetWithdrawRecordsContext(ctx context.Context, filter AssetRecordFilter) (result *WithdrawRecordsResponse, err error) {
+	if filter.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params()
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/withdraw/record/query", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// WithWithdrawWhitelist mirrors the account's withdrawal address whitelist, coin -> addresses.
+// A withdrawal of a listed coin to an address that isn't listed is refused before it's sent,
+// coins not in the map are left to bybit.
+func WithWithdrawWhitelist(addresses map[string][]string) ClientOption {
+	return func(c *Client) {
+		c.withdrawWhitelist = make(map[string]map[string]bool, len(addresses))
+		for coin, list := range addresses {
+			set := make(map[string]bool, len(list))
+			for _, addr := range list {
+				set[addr] = true
+			}
+			c.withdrawWhitelist[strings.ToUpper(coin)] = set
+		}
+	}
+}
+
+var ErrAddressNotWhitelisted = errors.New("withdraw address is not in the whitelist")
+
+func (p *Client) checkWithdrawAddress(coin, address string) error {
+	set, ok := p.withdrawWhitelist[strings.ToUpper(coin)]
+	if !ok || set[address] {
+		return nil
+	}
+	return ErrAddressNotWhitelisted
+}
+
+type WithdrawRequest struct {
+	Coin    string
+	Chain   string
+	Address string
+	// memo, for the chains which need one
+	Tag    string
+	Amount decimal.Decimal
+}
+
+func (r *WithdrawRequest) validate() error {
+	if r.Coin == "" || r.Chain == "" {
+		return errors.New("coin and chain are required")
+	}
+	if r.Address == "" {
+		return errors.New("address is required")
+	}
+	if !r.Amount.IsPositive() {
+		return errors.New("amount must be positive")
+	}
+	return nil
+}
+
+// CheckWithdraw checks req against the coin info, the chain must be open for withdrawals
+// and the amount at least its minimum, plus the whitelist from WithWithdrawWhitelist.
+func (p *Client) CheckWithdraw(req WithdrawRequest) error {
+	return p.CheckWithdrawContext(context.Background(), req)
+}
+
+func (p *Client) CheckWithdrawContext(ctx context.Context, req WithdrawRequest) error {
+	if err := req.validate(); err != nil {
+		return err
+	}
+	if err := p.checkWithdrawAddress(req.Coin, req.Address); err != nil {
+		return err
+	}
+	info, err := p.GetCoinInfoContext(ctx, req.Coin)
+	if err != nil {
+		return err
+	}
+	for _, coin := range info.Result.Rows {
+		if !strings.EqualFold(coin.Coin, req.Coin) {
+			continue
+		}
+		chain, ok := coin.Chain(req.Chain)
+		if !ok {
+			return errors.New("unknown chain " + req.Chain + " for " + req.Coin)
+		}
+		if !chain.WithdrawEnabled() {
+			return errors.New("withdrawals of " + req.Coin + " on " + req.Chain + " are suspended")
+		}
+		if req.Amount.LessThan(chain.WithdrawMin) {
+			return errors.New("amount is below the minimum withdrawal " + chain.WithdrawMin.String())
+		}
+		return nil
+	}
+	return errors.New("unknown coin " + req.Coin)
+}
+
+type WithdrawResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		ID string `json:"id"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// the address has to be in the account's whitelist on bybit when that's turned on,
+// WithWithdrawWhitelist catches it locally. Not retried, see CheckWithdraw to validate first.
+func (p *Client) Withdraw(req WithdrawRequest) (result *WithdrawResponse, err error) {
+	return p.WithdrawContext(context.Background(), req)
+}
+
+func (p *Client) WithdrawContext(ctx context.Context, req WithdrawRequest) (result *WithdrawResponse, err error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if err := p.checkWithdrawAddress(req.Coin, req.Address); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	params["coin"] = strings.ToUpper(req.Coin)
+	params["chain"] = req.Chain
+	params["address"] = req.Address
+	if req.Tag != "" {
+		params["tag"] = req.Tag
+	}
+	params["amount"] = req.Amount.String()
+	res, err := p.sendSignedBody(ctx, http.MethodPost, "/asset/v1/private/withdraw", params)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type CancelWithdrawResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		Status int `json:"status"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// only while the withdrawal is still pending review, Status 1 when cancelled
+func (p *Client) CancelWithdraw(id string) (result *CancelWithdrawResponse, err error) {
+	return p.CancelWithdrawContext(context.Background(), id)
+}
+
+func (p *Client) CancelWithdrawContext(ctx context.Context, id string) (result *CancelWithdrawResponse, err error) {
+	params := make(map[string]string)
+	params["id"] = id
+	res, err := p.sendSignedBody(ctx, http.MethodPost, "/asset/v1/private/withdraw/cancel", params)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
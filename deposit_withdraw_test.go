@@ -0,0 +1,76 @@
+package bybitapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const pathCoinInfo = "/asset/v1/private/coin-info/query"
+
+func newWithdrawClient(t *testing.T) (*bybittest.Server, *bybitapi.Client) {
+	t.Helper()
+	srv, client := newTestClient(t, bybitapi.WithWithdrawWhitelist(map[string][]string{
+		"usdt": {"addr-ok"},
+	}))
+	srv.Respond(http.MethodGet, pathCoinInfo, bybittest.OKAsset(map[string]interface{}{
+		"rows": []map[string]interface{}{{
+			"name": "USDT",
+			"coin": "USDT",
+			"chains": []map[string]interface{}{
+				{"chain": "TRX", "withdraw_min": "10", "withdraw_fee": "1", "chain_deposit": "1", "chain_withdraw": "1"},
+				{"chain": "ETH", "withdraw_min": "20", "withdraw_fee": "5", "chain_deposit": "1", "chain_withdraw": "0"},
+			},
+		}},
+	}))
+	return srv, client
+}
+
+func TestWithdrawNotWhitelisted(t *testing.T) {
+	srv, client := newWithdrawClient(t)
+
+	req := bybitapi.WithdrawRequest{Coin: "USDT", Chain: "TRX", Address: "addr-other", Amount: decimal.NewFromInt(100)}
+	if err := client.CheckWithdraw(req); !errors.Is(err, bybitapi.ErrAddressNotWhitelisted) {
+		t.Errorf("check: err = %v, want ErrAddressNotWhitelisted", err)
+	}
+	if _, err := client.Withdraw(req); !errors.Is(err, bybitapi.ErrAddressNotWhitelisted) {
+		t.Errorf("withdraw: err = %v, want ErrAddressNotWhitelisted", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("sent %d requests, want none", n)
+	}
+}
+
+func TestCheckWithdraw(t *testing.T) {
+	_, client := newWithdrawClient(t)
+
+	tests := []struct {
+		name  string
+		chain string
+		qty   int64
+		want  string
+	}{
+		{"ok", "trx", 10, ""},
+		{"suspended", "ETH", 100, "withdrawals of USDT on ETH are suspended"},
+		{"below minimum", "TRX", 5, "amount is below the minimum withdrawal 10"},
+		{"unknown chain", "SOL", 100, "unknown chain SOL for USDT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CheckWithdraw(bybitapi.WithdrawRequest{Coin: "USDT", Chain: tt.chain, Address: "addr-ok", Amount: decimal.NewFromInt(tt.qty)})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
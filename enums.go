@@ -10,6 +10,11 @@ const (
 	Limit    = "Limit"
 	Market   = "Market"
 
+	// trigger price source for perp tp / sl and conditional orders
+	LastPrice  = "LastPrice"
+	MarkPrice  = "MarkPrice"
+	IndexPrice = "IndexPrice"
+
 	ProductPerp = "perp"
 	ProductSpot = "spot"
 	// for spot
@@ -29,3 +34,44 @@ const (
 	OrderTypeMarketQuote = "MARKET_OF_QUOTE"
 	OrderTypeMarketBase  = "MARKET_OF_BASE"
 )
+
+// perp position mode, and position_idx for orders and positions
+const (
+	PositionModeMergedSingle = "MergedSingle"
+	PositionModeBothSide     = "BothSide"
+
+	PositionIdxOneWay    = 0
+	PositionIdxHedgeBuy  = 1
+	PositionIdxHedgeSell = 2
+)
+
+// perp order_status
+const (
+	PerpOrderStatusCreated         = "Created"
+	PerpOrderStatusNew             = "New"
+	PerpOrderStatusRejected        = "Rejected"
+	PerpOrderStatusPartiallyFilled = "PartiallyFilled"
+	PerpOrderStatusFilled          = "Filled"
+	PerpOrderStatusCancelled       = "Cancelled"
+	PerpOrderStatusPendingCancel   = "PendingCancel"
+)
+
+// perp exec_type
+const (
+	ExecTypeTrade     = "Trade"
+	ExecTypeAdlTrade  = "AdlTrade"
+	ExecTypeFunding   = "Funding"
+	ExecTypeBustTrade = "BustTrade"
+)
+
+// wallet_fund_type of the wallet fund records
+const (
+	WalletFundDeposit               = "Deposit"
+	WalletFundWithdraw              = "Withdraw"
+	WalletFundRealisedPNL           = "RealisedPNL"
+	WalletFundCommission            = "Commission"
+	WalletFundRefund                = "Refund"
+	WalletFundPrize                 = "Prize"
+	WalletFundExchangeOrderWithdraw = "ExchangeOrderWithdraw"
+	WalletFundExchangeOrderDeposit  = "ExchangeOrderDeposit"
+)
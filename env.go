@@ -0,0 +1,61 @@
+package bybitapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Environment is the pair of base urls a Client talks to,
+// RESTURL for the http api and StreamURL for every websocket.
+type Environment struct {
+	RESTURL   string
+	StreamURL string
+}
+
+var (
+	Mainnet = Environment{
+		RESTURL:   "https://api.bybit.com",
+		StreamURL: "wss://stream.bybit.com",
+	}
+	Testnet = Environment{
+		RESTURL:   "https://api-testnet.bybit.com",
+		StreamURL: "wss://stream-testnet.bybit.com",
+	}
+	// mirror for regions where bybit.com is not reachable
+	Bytick = Environment{
+		RESTURL:   "https://api.bytick.com",
+		StreamURL: "wss://stream.bytick.com",
+	}
+)
+
+// ex: CustomEnvironment("http://127.0.0.1:8080", "ws://127.0.0.1:8080")
+func CustomEnvironment(restURL, streamURL string) Environment {
+	return Environment{
+		RESTURL:   strings.TrimRight(restURL, "/"),
+		StreamURL: strings.TrimRight(streamURL, "/"),
+	}
+}
+
+func (e Environment) streamURL(spath string) string {
+	return e.StreamURL + spath
+}
+
+type ClientOption func(*Client)
+
+func WithEnvironment(env Environment) ClientOption {
+	return func(c *Client) {
+		c.env = CustomEnvironment(env.RESTURL, env.StreamURL)
+	}
+}
+
+func WithBaseURL(restURL, streamURL string) ClientOption {
+	return WithEnvironment(CustomEnvironment(restURL, streamURL))
+}
+
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		if hc != nil {
+			c.client = hc
+		}
+	}
+}
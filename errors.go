@@ -0,0 +1,147 @@
+package bybitapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// APIError is returned for any non-200 response or any response whose ret_code is not 0.
+type APIError struct {
+	StatusCode int
+	RetCode    int
+	RetMsg     string
+	ExtCode    string
+	ExtInfo    string
+	Endpoint   string
+	// from the body's rate_limit fields, or the X-Bapi-Limit headers when the body has none
+	RateLimit        int
+	RateLimitStatus  int
+	RateLimitResetMs int64
+}
+
+func (e *APIError) Error() string {
+	if e.RetCode == 0 && e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("faild to get data. status: %d, endpoint=%s, with error: %s", e.StatusCode, e.Endpoint, e.RetMsg)
+	}
+	return fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s, endpoint=%s", e.RetCode, e.RetMsg, e.ExtCode, e.ExtInfo, e.Endpoint)
+}
+
+// common fields of every response, decoded before the endpoint's own type
+type retEnvelope struct {
+	RetCode          int         `json:"ret_code"`
+	RetMsg           string      `json:"ret_msg"`
+	ExtCode          interface{} `json:"ext_code"`
+	ExtInfo          interface{} `json:"ext_info"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+	// v5 endpoints spell them retCode / retMsg
+	RetCodeV5 int    `json:"retCode"`
+	RetMsgV5  string `json:"retMsg"`
+}
+
+func (r *retEnvelope) normalize() {
+	if r.RetCode == 0 && r.RetCodeV5 != 0 {
+		r.RetCode = r.RetCodeV5
+	}
+	if r.RetMsg == "" {
+		r.RetMsg = r.RetMsgV5
+	}
+}
+
+func (r *retEnvelope) fillRateLimit(header http.Header) {
+	if r.RateLimit != 0 {
+		return
+	}
+	r.RateLimit, _ = strconv.Atoi(header.Get("X-Bapi-Limit"))
+	r.RateLimitStatus, _ = strconv.Atoi(header.Get("X-Bapi-Limit-Status"))
+	r.RateLimitResetMs, _ = strconv.ParseInt(header.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64)
+}
+
+// nil when the response is a success
+func checkResponse(spath string, res *http.Response, env *retEnvelope, body []byte) error {
+	if res.StatusCode == http.StatusOK && env.RetCode == 0 {
+		return nil
+	}
+	e := &APIError{
+		StatusCode:       res.StatusCode,
+		RetCode:          env.RetCode,
+		RetMsg:           env.RetMsg,
+		ExtCode:          stringify(env.ExtCode),
+		ExtInfo:          stringify(env.ExtInfo),
+		Endpoint:         spath,
+		RateLimit:        env.RateLimit,
+		RateLimitStatus:  env.RateLimitStatus,
+		RateLimitResetMs: env.RateLimitResetMs,
+	}
+	if e.RetMsg == "" {
+		e.RetMsg = strings.TrimSpace(string(body))
+	}
+	return e
+}
+
+// a history query has more records than bybit pages through, what was read before is still valid
+var ErrPageLimit = errors.New("bybit page limit reached, narrow the time range")
+
+// the transfer is known to bybit but wasn't executed, the record comes with the error
+var ErrTransferFailed = errors.New("bybit transfer failed")
+
+func stringify(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+var (
+	insufficientBalanceCodes = []int{30031, 30049, 130021, 130080, -1131}
+	orderNotFoundCodes       = []int{20001, 30032, 30034, 130010, -2013, -2011}
+	rateLimitedCodes         = []int{10006, 10018, -1003}
+	invalidTimestampCodes    = []int{10002, -1021}
+	reduceOnlyCodes          = []int{30063, 130125}
+)
+
+func apiErrorIn(err error, codes []int) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, code := range codes {
+		if e.RetCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func IsInsufficientBalance(err error) bool {
+	return apiErrorIn(err, insufficientBalanceCodes)
+}
+
+func IsOrderNotFound(err error) bool {
+	return apiErrorIn(err, orderNotFoundCodes)
+}
+
+// also true for http 403 / 429, which bybit uses for ip bans, and for ErrRateLimitExceeded
+func IsRateLimited(err error) bool {
+	if errors.Is(err, ErrRateLimitExceeded) {
+		return true
+	}
+	var e *APIError
+	if errors.As(err, &e) && (e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusTooManyRequests) {
+		return true
+	}
+	return apiErrorIn(err, rateLimitedCodes)
+}
+
+// usually the local clock is drifting or recv_window is too small
+func IsInvalidTimestamp(err error) bool {
+	return apiErrorIn(err, invalidTimestampCodes)
+}
+
+func IsReduceOnlyViolation(err error) bool {
+	return apiErrorIn(err, reduceOnlyCodes)
+}
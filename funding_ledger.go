@@ -0,0 +1,241 @@
+package bybitapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FundingPayment struct {
+	ExecID string
+	Symbol string
+	Side   string
+	Size   decimal.Decimal
+	// mark price at settlement
+	Price decimal.Decimal
+	Rate  decimal.Decimal
+	// positive when paid, negative when received
+	Fee  decimal.Decimal
+	Time time.Time
+}
+
+type FundingRate struct {
+	Symbol string
+	Rate   decimal.Decimal
+	Time   time.Time
+}
+
+// FundingLedger keeps the funding fees of the account and the settled funding rates per symbol.
+// USDT perp funding fees are not written to the wallet fund records, they show up
+// in the execution list as ExecTypeFunding, so that's where the payments come from.
+type FundingLedger struct {
+	mux      sync.RWMutex
+	payments map[string]map[string]FundingPayment
+	rates    map[string]map[int64]FundingRate
+}
+
+func NewFundingLedger() *FundingLedger {
+	return &FundingLedger{
+		payments: make(map[string]map[string]FundingPayment),
+		rates:    make(map[string]map[int64]FundingRate),
+	}
+}
+
+// LoadFundingLedger syncs every symbol over [start, end] into a new ledger.
+func (p *Client) LoadFundingLedger(symbols []string, start, end time.Time) (*FundingLedger, error) {
+	return p.LoadFundingLedgerContext(context.Background(), symbols, start, end)
+}
+
+func (p *Client) LoadFundingLedgerContext(ctx context.Context, symbols []string, start, end time.Time) (*FundingLedger, error) {
+	ledger := NewFundingLedger()
+	for _, symbol := range symbols {
+		if err := ledger.SyncContext(ctx, p, symbol, start, end); err != nil {
+			return nil, err
+		}
+	}
+	return ledger, nil
+}
+
+// Sync pulls the payments and rates of symbol in [start, end], records already in the ledger are kept once.
+func (l *FundingLedger) Sync(client *Client, symbol string, start, end time.Time) error {
+	return l.SyncContext(context.Background(), client, symbol, start, end)
+}
+
+func (l *FundingLedger) SyncContext(ctx context.Context, client *Client, symbol string, start, end time.Time) error {
+	if err := l.SyncPaymentsContext(ctx, client, symbol, start, end); err != nil {
+		return err
+	}
+	return l.SyncRatesContext(ctx, client, symbol, start, end)
+}
+
+func (l *FundingLedger) SyncPayments(client *Client, symbol string, start, end time.Time) error {
+	return l.SyncPaymentsContext(context.Background(), client, symbol, start, end)
+}
+
+func (l *FundingLedger) SyncPaymentsContext(ctx context.Context, client *Client, symbol string, start, end time.Time) error {
+	it := client.PerpExecutionHistoryIterContext(ctx, symbol, PerpExecutionFilter{
+		StartTime: start,
+		EndTime:   end,
+		ExecType:  ExecTypeFunding,
+	})
+	for it.Next() {
+		exec := it.Execution()
+		l.addPayment(FundingPayment{
+			ExecID: exec.ExecID,
+			Symbol: exec.Symbol,
+			Side:   exec.Side,
+			Size:   decimal.NewFromFloat(exec.ExecQty),
+			Price:  decimal.NewFromFloat(exec.ExecPrice),
+			Rate:   decimal.NewFromFloat(exec.FeeRate),
+			Fee:    decimal.NewFromFloat(exec.ExecFee),
+			Time:   time.UnixMilli(exec.TradeTimeMs),
+		})
+	}
+	return it.Err()
+}
+
+// walks back from end, 200 rates per request
+func (l *FundingLedger) SyncRates(client *Client, symbol string, start, end time.Time) error {
+	return l.SyncRatesContext(context.Background(), client, symbol, start, end)
+}
+
+func (l *FundingLedger) SyncRatesContext(ctx context.Context, client *Client, symbol string, start, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	for !end.Before(start) {
+		result, err := client.PerpFundingRateHistoryContext(ctx, symbol, start, end, 200)
+		if err != nil {
+			return err
+		}
+		list := result.Result.List
+		if len(list) == 0 {
+			return nil
+		}
+		oldest := end
+		for _, item := range list {
+			ms, err := strconv.ParseInt(item.FundingRateTimestamp, 10, 64)
+			if err != nil {
+				continue
+			}
+			ts := time.UnixMilli(ms)
+			l.addRate(FundingRate{
+				Symbol: item.Symbol,
+				Rate:   item.FundingRate,
+				Time:   ts,
+			})
+			if ts.Before(oldest) {
+				oldest = ts
+			}
+		}
+		if len(list) < 200 || !oldest.Before(end) {
+			return nil
+		}
+		end = oldest.Add(-time.Millisecond)
+	}
+	return nil
+}
+
+func (l *FundingLedger) addPayment(pay FundingPayment) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	symbol := strings.ToUpper(pay.Symbol)
+	set, ok := l.payments[symbol]
+	if !ok {
+		set = make(map[string]FundingPayment)
+		l.payments[symbol] = set
+	}
+	key := pay.ExecID
+	if key == "" {
+		key = pay.Side + strconv.FormatInt(pay.Time.UnixMilli(), 10)
+	}
+	set[key] = pay
+}
+
+func (l *FundingLedger) addRate(rate FundingRate) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	symbol := strings.ToUpper(rate.Symbol)
+	set, ok := l.rates[symbol]
+	if !ok {
+		set = make(map[int64]FundingRate)
+		l.rates[symbol] = set
+	}
+	set[rate.Time.UnixMilli()] = rate
+}
+
+// payments of symbol in [t0, t1], oldest first, zero times leave that side open
+func (l *FundingLedger) Payments(symbol string, t0, t1 time.Time) []FundingPayment {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	var out []FundingPayment
+	for _, pay := range l.payments[strings.ToUpper(symbol)] {
+		if inRange(pay.Time, t0, t1) {
+			out = append(out, pay)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Time.Before(out[j].Time)
+	})
+	return out
+}
+
+// rates of symbol in [t0, t1], oldest first
+func (l *FundingLedger) Rates(symbol string, t0, t1 time.Time) []FundingRate {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	var out []FundingRate
+	for _, rate := range l.rates[strings.ToUpper(symbol)] {
+		if inRange(rate.Time, t0, t1) {
+			out = append(out, rate)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Time.Before(out[j].Time)
+	})
+	return out
+}
+
+// total funding paid and received by symbol in [t0, t1], both positive
+func (l *FundingLedger) Totals(symbol string, t0, t1 time.Time) (paid, received decimal.Decimal) {
+	for _, pay := range l.Payments(symbol, t0, t1) {
+		if pay.Fee.IsPositive() {
+			paid = paid.Add(pay.Fee)
+		} else {
+			received = received.Sub(pay.Fee)
+		}
+	}
+	return paid, received
+}
+
+// received minus paid, positive when the position earned funding
+func (l *FundingLedger) Net(symbol string, t0, t1 time.Time) decimal.Decimal {
+	paid, received := l.Totals(symbol, t0, t1)
+	return received.Sub(paid)
+}
+
+func (l *FundingLedger) Symbols() []string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	out := make([]string, 0, len(l.payments))
+	for symbol := range l.payments {
+		out = append(out, symbol)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func inRange(t, t0, t1 time.Time) bool {
+	if !t0.IsZero() && t.Before(t0) {
+		return false
+	}
+	if !t1.IsZero() && t.After(t1) {
+		return false
+	}
+	return true
+}
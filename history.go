@@ -1,7 +1,7 @@
 package bybitapi
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -37,12 +37,16 @@ type KlineData struct {
 
 // opts for inteval: 1m, 3m, 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d, 1w, 1M
 func (p *Client) SpotHistoryKline(symbol, interval string, start, end time.Time) (result *SpotHistoryKlineResponse, err error) {
+	return p.SpotHistoryKlineContext(context.Background(), symbol, interval, start, end)
+}
+
+func (p *Client) SpotHistoryKlineContext(ctx context.Context, symbol, interval string, start, end time.Time) (result *SpotHistoryKlineResponse, err error) {
 	params := make(map[string]string)
 	params["symbol"] = strings.ToUpper(symbol)
 	params["interval"] = interval
 	params["startTime"] = fmt.Sprintf("%v", start.UnixMilli())
 	params["endTime"] = fmt.Sprintf("%v", end.UnixMilli())
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/quote/v1/kline", nil, &params, false)
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/quote/v1/kline", nil, &params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -52,13 +56,6 @@ func (p *Client) SpotHistoryKline(symbol, interval string, start, end time.Time)
 	if err != nil {
 		return nil, err
 	}
-	if result == nil {
-		return nil, errors.New("response is nil")
-	}
-	if raw.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", raw.RetCode, raw.RetMsg, raw.ExtCode, raw.ExtInfo)
-		return nil, errors.New(message)
-	}
 
 	result = new(SpotHistoryKlineResponse)
 	result.RetCode = raw.RetCode
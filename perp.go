@@ -1,11 +1,14 @@
 package bybitapi
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type LastInfoForSymbolResponse struct {
@@ -45,6 +48,10 @@ type LastInfoForSymbolResponse struct {
 }
 
 func (p *Client) LastInfoForSymbol(symbol string) (resp *LastInfoForSymbolResponse, err error) {
+	return p.LastInfoForSymbolContext(context.Background(), symbol)
+}
+
+func (p *Client) LastInfoForSymbolContext(ctx context.Context, symbol string) (resp *LastInfoForSymbolResponse, err error) {
 	params := make(map[string]string)
 	if symbol != "" {
 		params["symbol"] = strings.ToUpper(symbol)
@@ -53,7 +60,7 @@ func (p *Client) LastInfoForSymbol(symbol string) (resp *LastInfoForSymbolRespon
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/v2/public/tickers", body, &params, false)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/public/tickers", body, &params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -65,13 +72,38 @@ func (p *Client) LastInfoForSymbol(symbol string) (resp *LastInfoForSymbolRespon
 	if resp == nil {
 		return nil, errors.New("response is nil")
 	}
-	if resp.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", resp.RetCode, resp.RetMsg, resp.ExtCode, resp.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return resp, nil
 }
 
+type GetPerpServerTimeResponse struct {
+	RetCode int         `json:"ret_code"`
+	RetMsg  string      `json:"ret_msg"`
+	ExtCode string      `json:"ext_code"`
+	ExtInfo string      `json:"ext_info"`
+	Result  interface{} `json:"result"`
+	// seconds, ex: "1577444332.192859"
+	TimeNow string `json:"time_now"`
+}
+
+func (p *Client) GetPerpServerTime() (result *GetPerpServerTimeResponse, err error) {
+	return p.GetPerpServerTimeContext(context.Background())
+}
+
+func (p *Client) GetPerpServerTimeContext(ctx context.Context) (result *GetPerpServerTimeResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/public/time", nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
 type PerpsInfoResponse struct {
 	RetCode int    `json:"ret_code"`
 	RetMsg  string `json:"ret_msg"`
@@ -106,7 +138,11 @@ type PerpsInfoResponse struct {
 }
 
 func (p *Client) PerpsInfo() (resp *PerpsInfoResponse, err error) {
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/v2/public/symbols", nil, nil, false)
+	return p.PerpsInfoContext(context.Background())
+}
+
+func (p *Client) PerpsInfoContext(ctx context.Context) (resp *PerpsInfoResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/public/symbols", nil, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -118,10 +154,6 @@ func (p *Client) PerpsInfo() (resp *PerpsInfoResponse, err error) {
 	if resp == nil {
 		return nil, errors.New("response is nil")
 	}
-	if resp.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", resp.RetCode, resp.RetMsg, resp.ExtCode, resp.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return resp, nil
 }
 
@@ -139,6 +171,10 @@ type LastFundingRateResponse struct {
 }
 
 func (p *Client) LastFundingRate(symbol string) (result *LastFundingRateResponse, err error) {
+	return p.LastFundingRateContext(context.Background(), symbol)
+}
+
+func (p *Client) LastFundingRateContext(ctx context.Context, symbol string) (result *LastFundingRateResponse, err error) {
 	params := make(map[string]string)
 	if symbol != "" {
 		params["symbol"] = strings.ToUpper(symbol)
@@ -147,7 +183,7 @@ func (p *Client) LastFundingRate(symbol string) (result *LastFundingRateResponse
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/public/linear/funding/prev-funding-rate", body, &params, false)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/public/linear/funding/prev-funding-rate", body, &params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -159,9 +195,54 @@ func (p *Client) LastFundingRate(symbol string) (result *LastFundingRateResponse
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
+	return result, nil
+}
+
+type PerpFundingRateHistoryResponse struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		Category string `json:"category"`
+		List     []struct {
+			Symbol      string          `json:"symbol"`
+			FundingRate decimal.Decimal `json:"fundingRate"`
+			// milliseconds
+			FundingRateTimestamp string `json:"fundingRateTimestamp"`
+		} `json:"list"`
+	} `json:"result"`
+	Time int64 `json:"time"`
+}
+
+// settled funding rates in [start, end], newest first, max 200 at once.
+// only the v5 market api keeps the full history.
+func (p *Client) PerpFundingRateHistory(symbol string, start, end time.Time, limit int) (result *PerpFundingRateHistoryResponse, err error) {
+	return p.PerpFundingRateHistoryContext(context.Background(), symbol, start, end, limit)
+}
+
+func (p *Client) PerpFundingRateHistoryContext(ctx context.Context, symbol string, start, end time.Time, limit int) (result *PerpFundingRateHistoryResponse, err error) {
+	params := make(map[string]string)
+	params["category"] = "linear"
+	params["symbol"] = strings.ToUpper(symbol)
+	if !start.IsZero() {
+		params["startTime"] = strconv.FormatInt(start.UnixMilli(), 10)
+	}
+	if !end.IsZero() {
+		params["endTime"] = strconv.FormatInt(end.UnixMilli(), 10)
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v5/market/funding/history", nil, &params, false)
+	if err != nil {
+		return nil, err
+	}
+	// in Close()
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
 	}
 	return result, nil
 }
@@ -179,6 +260,10 @@ type SetAutoAddMarginResponse struct {
 }
 
 func (p *Client) SetAutoAddMargin(symbol, side string, AutoAdd bool) (result *SetAutoAddMarginResponse, err error) {
+	return p.SetAutoAddMarginContext(context.Background(), symbol, side, AutoAdd)
+}
+
+func (p *Client) SetAutoAddMarginContext(ctx context.Context, symbol, side string, AutoAdd bool) (result *SetAutoAddMarginResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
 	params["side"] = side
@@ -187,7 +272,7 @@ func (p *Client) SetAutoAddMargin(symbol, side string, AutoAdd bool) (result *Se
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/position/set-auto-add-margin", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/set-auto-add-margin", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -199,10 +284,6 @@ func (p *Client) SetAutoAddMargin(symbol, side string, AutoAdd bool) (result *Se
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -219,36 +300,45 @@ type PerpPositionsResponse struct {
 }
 
 type PerpPositionsDetail struct {
-	Data struct {
-		UserID              int     `json:"user_id"`
-		Symbol              string  `json:"symbol"`
-		Side                string  `json:"side"`
-		Size                float64 `json:"size"`
-		PositionValue       float64 `json:"position_value"`
-		EntryPrice          float64 `json:"entry_price"`
-		LiqPrice            float64 `json:"liq_price"`
-		BustPrice           float64 `json:"bust_price"`
-		Leverage            float64 `json:"leverage"`
-		AutoAddMargin       float64 `json:"auto_add_margin"`
-		IsIsolated          bool    `json:"is_isolated"`
-		PositionMargin      float64 `json:"position_margin"`
-		OccClosingFee       float64 `json:"occ_closing_fee"`
-		RealisedPnl         float64 `json:"realised_pnl"`
-		CumRealisedPnl      float64 `json:"cum_realised_pnl"`
-		FreeQty             float64 `json:"free_qty"`
-		TpSlMode            string  `json:"tp_sl_mode"`
-		UnrealisedPnl       float64 `json:"unrealised_pnl"`
-		DeleverageIndicator float64 `json:"deleverage_indicator"`
-		RiskID              float64 `json:"risk_id"`
-		StopLoss            float64 `json:"stop_loss"`
-		TakeProfit          float64 `json:"take_profit"`
-		TrailingStop        float64 `json:"trailing_stop"`
-	} `json:"data"`
-	IsValid bool `json:"is_valid"`
+	Data    PerpPositionData `json:"data"`
+	IsValid bool             `json:"is_valid"`
+}
+
+type PerpPositionData struct {
+	UserID              int     `json:"user_id"`
+	Symbol              string  `json:"symbol"`
+	Side                string  `json:"side"`
+	Size                float64 `json:"size"`
+	PositionValue       float64 `json:"position_value"`
+	EntryPrice          float64 `json:"entry_price"`
+	LiqPrice            float64 `json:"liq_price"`
+	BustPrice           float64 `json:"bust_price"`
+	Leverage            float64 `json:"leverage"`
+	AutoAddMargin       float64 `json:"auto_add_margin"`
+	IsIsolated          bool    `json:"is_isolated"`
+	PositionMargin      float64 `json:"position_margin"`
+	OccClosingFee       float64 `json:"occ_closing_fee"`
+	RealisedPnl         float64 `json:"realised_pnl"`
+	CumRealisedPnl      float64 `json:"cum_realised_pnl"`
+	FreeQty             float64 `json:"free_qty"`
+	TpSlMode            string  `json:"tp_sl_mode"`
+	UnrealisedPnl       float64 `json:"unrealised_pnl"`
+	DeleverageIndicator float64 `json:"deleverage_indicator"`
+	RiskID              float64 `json:"risk_id"`
+	StopLoss            float64 `json:"stop_loss"`
+	TakeProfit          float64 `json:"take_profit"`
+	TrailingStop        float64 `json:"trailing_stop"`
+	PositionIdx         int     `json:"position_idx"`
+	// PositionModeMergedSingle or PositionModeBothSide
+	Mode string `json:"mode"`
 }
 
 func (p *Client) PerpPositions() (result *PerpPositionsResponse, err error) {
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/private/linear/position/list", nil, nil, true)
+	return p.PerpPositionsContext(context.Background())
+}
+
+func (p *Client) PerpPositionsContext(ctx context.Context) (result *PerpPositionsResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/position/list", nil, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -259,10 +349,6 @@ func (p *Client) PerpPositions() (result *PerpPositionsResponse, err error) {
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -279,15 +365,35 @@ type SetLeverageResponse struct {
 }
 
 func (p *Client) SetLeverage(symbol string, leverage int) (result *SetLeverageResponse, err error) {
+	return p.SetLeverageContext(context.Background(), symbol, leverage)
+}
+
+func (p *Client) SetLeverageContext(ctx context.Context, symbol string, leverage int) (result *SetLeverageResponse, err error) {
+	lev := decimal.NewFromInt(int64(leverage))
+	return p.SetBuySellLeverageContext(ctx, symbol, lev, lev)
+}
+
+// in BothSide mode the long and short position can run different leverage,
+// in MergedSingle mode bybit wants both equal
+func (p *Client) SetBuySellLeverage(symbol string, buyLev, sellLev decimal.Decimal) (result *SetLeverageResponse, err error) {
+	return p.SetBuySellLeverageContext(context.Background(), symbol, buyLev, sellLev)
+}
+
+func (p *Client) SetBuySellLeverageContext(ctx context.Context, symbol string, buyLev, sellLev decimal.Decimal) (result *SetLeverageResponse, err error) {
+	if !buyLev.IsPositive() || !sellLev.IsPositive() {
+		return nil, errors.New("leverage must be positive")
+	}
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
-	params["buy_leverage"] = leverage
-	params["sell_leverage"] = leverage
+	fbuy, _ := buyLev.Float64()
+	params["buy_leverage"] = fbuy
+	fsell, _ := sellLev.Float64()
+	params["sell_leverage"] = fsell
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/position/set-leverage", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/set-leverage", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -295,17 +401,9 @@ func (p *Client) SetLeverage(symbol string, leverage int) (result *SetLeverageRe
 	if err != nil {
 		return nil, err
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -338,7 +436,11 @@ type PerpWalletBalanceDetail struct {
 }
 
 func (p *Client) GetPerpWalletBalance() (result *GetPerpWalletBalanceResponse, err error) {
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/v2/private/wallet/balance", nil, nil, true)
+	return p.GetPerpWalletBalanceContext(context.Background())
+}
+
+func (p *Client) GetPerpWalletBalanceContext(ctx context.Context) (result *GetPerpWalletBalanceResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/private/wallet/balance", nil, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -349,10 +451,6 @@ func (p *Client) GetPerpWalletBalance() (result *GetPerpWalletBalanceResponse, e
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -376,9 +474,13 @@ type GetLastFundingPaymentResponse struct {
 }
 
 func (p *Client) GetLastFundingPayment(symbol string) (result *GetLastFundingPaymentResponse, err error) {
+	return p.GetLastFundingPaymentContext(context.Background(), symbol)
+}
+
+func (p *Client) GetLastFundingPaymentContext(ctx context.Context, symbol string) (result *GetLastFundingPaymentResponse, err error) {
 	params := make(map[string]string)
 	params["symbol"] = strings.ToUpper(symbol)
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/private/linear/funding/prev-funding", nil, &params, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/funding/prev-funding", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -389,10 +491,6 @@ func (p *Client) GetLastFundingPayment(symbol string) (result *GetLastFundingPay
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -419,9 +517,13 @@ type GetPerpRiskLimitDetail struct {
 }
 
 func (p *Client) GetPerpRiskLimit(symbol string) (result *GetPerpRiskLimitResposnse, err error) {
+	return p.GetPerpRiskLimitContext(context.Background(), symbol)
+}
+
+func (p *Client) GetPerpRiskLimitContext(ctx context.Context, symbol string) (result *GetPerpRiskLimitResposnse, err error) {
 	params := make(map[string]string)
 	params["symbol"] = strings.ToUpper(symbol)
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/public/linear/risk-limit", nil, &params, false)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/public/linear/risk-limit", nil, &params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -432,10 +534,6 @@ func (p *Client) GetPerpRiskLimit(symbol string) (result *GetPerpRiskLimitRespos
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -454,6 +552,10 @@ type SetPerpRiskLimitResponse struct {
 }
 
 func (p *Client) SetPerpRiskLimit(symbol, side string, riskID int) (result *SetLeverageResponse, err error) {
+	return p.SetPerpRiskLimitContext(context.Background(), symbol, side, riskID)
+}
+
+func (p *Client) SetPerpRiskLimitContext(ctx context.Context, symbol, side string, riskID int) (result *SetLeverageResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
 	params["side"] = side
@@ -462,7 +564,7 @@ func (p *Client) SetPerpRiskLimit(symbol, side string, riskID int) (result *SetL
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/position/set-risk", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/set-risk", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -473,10 +575,6 @@ func (p *Client) SetPerpRiskLimit(symbol, side string, riskID int) (result *SetL
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -492,8 +590,12 @@ type PositionModeSwitchResponse struct {
 	RateLimit        int         `json:"rate_limit"`
 }
 
-// opts: MergedSingle, BothSide
+// opts: PositionModeMergedSingle, PositionModeBothSide
 func (p *Client) PositionModeSwitch(symbol, mode string) (result *PositionModeSwitchResponse, err error) {
+	return p.PositionModeSwitchContext(context.Background(), symbol, mode)
+}
+
+func (p *Client) PositionModeSwitchContext(ctx context.Context, symbol, mode string) (result *PositionModeSwitchResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
 	params["mode"] = mode
@@ -501,7 +603,7 @@ func (p *Client) PositionModeSwitch(symbol, mode string) (result *PositionModeSw
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/position/switch-mode", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/switch-mode", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -512,9 +614,237 @@ func (p *Client) PositionModeSwitch(symbol, mode string) (result *PositionModeSw
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
+	return result, nil
+}
+
+// tp_sl_mode
+const (
+	TpSlModeFull    = "Full"
+	TpSlModePartial = "Partial"
+)
+
+// nil fields are left untouched, a zero TakeProfit / StopLoss / TrailingStop cancels it.
+// TpSize / SlSize only apply in TpSlModePartial.
+type PerpTradingStopRequest struct {
+	Symbol string
+	// side of the position
+	Side         string
+	TakeProfit   *decimal.Decimal
+	StopLoss     *decimal.Decimal
+	TrailingStop *decimal.Decimal
+	// LastPrice when empty, or MarkPrice, IndexPrice
+	TpTriggerBy string
+	SlTriggerBy string
+	TpSize      *decimal.Decimal
+	SlSize      *decimal.Decimal
+	PositionIdx int
+}
+
+func (r *PerpTradingStopRequest) params() (map[string]interface{}, error) {
+	if r.Symbol == "" || r.Side == "" {
+		return nil, errors.New("symbol and side are required")
+	}
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(r.Symbol)
+	params["side"] = r.Side
+	for key, value := range map[string]*decimal.Decimal{
+		"take_profit":   r.TakeProfit,
+		"stop_loss":     r.StopLoss,
+		"trailing_stop": r.TrailingStop,
+		"tp_size":       r.TpSize,
+		"sl_size":       r.SlSize,
+	} {
+		if value == nil {
+			continue
+		}
+		if value.IsNegative() {
+			return nil, errors.New(key + " can't be negative")
+		}
+		f, _ := value.Float64()
+		params[key] = f
+	}
+	if r.TpTriggerBy != "" {
+		params["tp_trigger_by"] = r.TpTriggerBy
+	}
+	if r.SlTriggerBy != "" {
+		params["sl_trigger_by"] = r.SlTriggerBy
+	}
+	if r.PositionIdx != PositionIdxOneWay {
+		params["position_idx"] = r.PositionIdx
+	}
+	return params, nil
+}
+
+type SetPerpTradingStopResponse struct {
+	RetCode          int         `json:"ret_code"`
+	RetMsg           string      `json:"ret_msg"`
+	ExtCode          string      `json:"ext_code"`
+	ExtInfo          string      `json:"ext_info"`
+	Result           interface{} `json:"result"`
+	TimeNow          string      `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) SetPerpTradingStop(req PerpTradingStopRequest) (result *SetPerpTradingStopResponse, err error) {
+	return p.SetPerpTradingStopContext(context.Background(), req)
+}
+
+func (p *Client) SetPerpTradingStopContext(ctx context.Context, req PerpTradingStopRequest) (result *SetPerpTradingStopResponse, err error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/trading-stop", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type SwitchTpSlModeResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		TpSlMode string `json:"tp_sl_mode"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// opts: TpSlModeFull, TpSlModePartial
+func (p *Client) SwitchTpSlMode(symbol, mode string) (result *SwitchTpSlModeResponse, err error) {
+	return p.SwitchTpSlModeContext(context.Background(), symbol, mode)
+}
+
+func (p *Client) SwitchTpSlModeContext(ctx context.Context, symbol, mode string) (result *SwitchTpSlModeResponse, err error) {
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(symbol)
+	params["tp_sl_mode"] = mode
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/tpsl/switch-mode", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type SwitchIsolatedResponse struct {
+	RetCode          int         `json:"ret_code"`
+	RetMsg           string      `json:"ret_msg"`
+	ExtCode          string      `json:"ext_code"`
+	ExtInfo          string      `json:"ext_info"`
+	Result           interface{} `json:"result"`
+	TimeNow          string      `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// isIsolated false switches the symbol to cross margin, leverage is set for both sides at the same time
+func (p *Client) SwitchIsolated(symbol string, isIsolated bool, buyLev, sellLev decimal.Decimal) (result *SwitchIsolatedResponse, err error) {
+	return p.SwitchIsolatedContext(context.Background(), symbol, isIsolated, buyLev, sellLev)
+}
+
+func (p *Client) SwitchIsolatedContext(ctx context.Context, symbol string, isIsolated bool, buyLev, sellLev decimal.Decimal) (result *SwitchIsolatedResponse, err error) {
+	if !buyLev.IsPositive() || !sellLev.IsPositive() {
+		return nil, errors.New("leverage must be positive")
+	}
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(symbol)
+	params["is_isolated"] = isIsolated
+	fbuy, _ := buyLev.Float64()
+	params["buy_leverage"] = fbuy
+	fsell, _ := sellLev.Float64()
+	params["sell_leverage"] = fsell
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/switch-isolated", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type AddReducePerpMarginResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		Position         PerpPositionData `json:"PositionListResult"`
+		WalletBalance    float64          `json:"wallet_balance"`
+		AvailableBalance float64          `json:"available_balance"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// isolated positions only, positive margin adds and negative margin reduces
+func (p *Client) AddReducePerpMargin(symbol, side string, margin decimal.Decimal) (result *AddReducePerpMarginResponse, err error) {
+	return p.AddReducePerpMarginContext(context.Background(), symbol, side, margin)
+}
+
+func (p *Client) AddReducePerpMarginContext(ctx context.Context, symbol, side string, margin decimal.Decimal) (result *AddReducePerpMarginResponse, err error) {
+	if margin.IsZero() {
+		return nil, errors.New("margin can't be zero")
+	}
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(symbol)
+	params["side"] = side
+	fmargin, _ := margin.Float64()
+	params["margin"] = fmargin
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/position/add-margin", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
 	}
 	return result, nil
 }
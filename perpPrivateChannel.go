@@ -18,6 +18,8 @@ type perpPrivateChannelBranch struct {
 	secret     string
 	subaccount string
 	product    string
+	env        Environment
+	clock      *serverClock
 	tradeSets  tradeDataMap
 	logger     *logrus.Logger
 }
@@ -89,6 +91,8 @@ func (c *Client) perpPrivateChannelStream(logger *logrus.Logger) {
 	o.key = c.key
 	o.secret = c.secret
 	o.subaccount = c.subaccount
+	o.env = c.env
+	o.clock = c.clock
 	o.product = ProductSpot
 	o.tradeSets.set = make(map[string][]UserTradeData, 5)
 	o.logger = logger
@@ -115,16 +119,17 @@ func (o *perpPrivateChannelBranch) maintain(ctx context.Context) error {
 	var duration time.Duration = 300
 	var w ws
 	innerErr := make(chan error, 1)
-	url := "wss://stream.bybit.com/realtime_private"
+	url := o.env.streamURL("/realtime_private")
 	// wait 5 second, if the hand shake fail, will terminate the dail
-	dailCtx, _ := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
+	dailCtx, dailCancel := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
 	conn, _, err := websocket.DefaultDialer.DialContext(dailCtx, url, nil)
+	dailCancel()
 	if err != nil {
 		return err
 	}
 	w.conn = conn
 	defer w.conn.Close()
-	if err := w.getAuth(o.key, o.secret); err != nil {
+	if err := w.getAuth(o.key, o.secret, o.clock.now()); err != nil {
 		return err
 	}
 	if err := w.conn.SetReadDeadline(time.Now().Add(time.Second * duration)); err != nil {
@@ -0,0 +1,115 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// linear has no batch endpoint, batches are sent as single requests by this many workers,
+// paced by the client's rate limiter
+const defaultBatchConcurrency = 5
+
+func WithBatchConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchConcurrency = n
+		}
+	}
+}
+
+// either OrderID or OrderLinkID
+type PerpCancelTarget struct {
+	Symbol      string
+	OrderID     string
+	OrderLinkID string
+}
+
+type PerpBatchPlaceResult struct {
+	Response *PerpPlaceOrderResponse
+	Err      error
+}
+
+type PerpBatchCancelResult struct {
+	Response *PerpGetOrderResponse
+	Err      error
+}
+
+type PerpBatchResult struct {
+	Cancels []PerpBatchCancelResult
+	Places  []PerpBatchPlaceResult
+}
+
+// calls fn for 0..n-1 on at most workers goroutines, items not started before ctx is done get ctx.Err()
+func runBatch(ctx context.Context, n, workers int, fn func(i int), skip func(i int, err error)) {
+	if workers <= 0 {
+		workers = defaultBatchConcurrency
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			skip(i, ctx.Err())
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// results are in the order of reqs
+func (p *Client) PerpBatchPlaceOrders(reqs []PerpOrderRequest) []PerpBatchPlaceResult {
+	return p.PerpBatchPlaceOrdersContext(context.Background(), reqs)
+}
+
+func (p *Client) PerpBatchPlaceOrdersContext(ctx context.Context, reqs []PerpOrderRequest) []PerpBatchPlaceResult {
+	results := make([]PerpBatchPlaceResult, len(reqs))
+	runBatch(ctx, len(reqs), p.batchConcurrency, func(i int) {
+		results[i].Response, results[i].Err = p.PerpSubmitOrderContext(ctx, reqs[i])
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// results are in the order of targets
+func (p *Client) PerpBatchCancelOrders(targets []PerpCancelTarget) []PerpBatchCancelResult {
+	return p.PerpBatchCancelOrdersContext(context.Background(), targets)
+}
+
+func (p *Client) PerpBatchCancelOrdersContext(ctx context.Context, targets []PerpCancelTarget) []PerpBatchCancelResult {
+	results := make([]PerpBatchCancelResult, len(targets))
+	runBatch(ctx, len(targets), p.batchConcurrency, func(i int) {
+		target := targets[i]
+		switch {
+		case target.OrderID != "":
+			results[i].Response, results[i].Err = p.PerpCancelOrderContext(ctx, target.Symbol, target.OrderID)
+		case target.OrderLinkID != "":
+			results[i].Response, results[i].Err = p.PerpCancelOrderByLinkIDContext(ctx, target.Symbol, target.OrderLinkID)
+		default:
+			results[i].Err = errors.New("order id or order link id is required")
+		}
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// PerpBatch cancels first and then places, so a refreshed ladder doesn't need margin for both at once.
+func (p *Client) PerpBatch(cancels []PerpCancelTarget, places []PerpOrderRequest) *PerpBatchResult {
+	return p.PerpBatchContext(context.Background(), cancels, places)
+}
+
+func (p *Client) PerpBatchContext(ctx context.Context, cancels []PerpCancelTarget, places []PerpOrderRequest) *PerpBatchResult {
+	result := new(PerpBatchResult)
+	result.Cancels = p.PerpBatchCancelOrdersContext(ctx, cancels)
+	result.Places = p.PerpBatchPlaceOrdersContext(ctx, places)
+	return result
+}
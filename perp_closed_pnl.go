@@ -0,0 +1,130 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const perpClosedPnlMaxLimit = 50
+
+type PerpClosedPnlData struct {
+	ID            int64           `json:"id"`
+	UserID        int64           `json:"user_id"`
+	Symbol        string          `json:"symbol"`
+	OrderID       string          `json:"order_id"`
+	Side          string          `json:"side"`
+	Qty           decimal.Decimal `json:"qty"`
+	OrderPrice    decimal.Decimal `json:"order_price"`
+	OrderType     string          `json:"order_type"`
+	ExecType      string          `json:"exec_type"`
+	ClosedSize    decimal.Decimal `json:"closed_size"`
+	CumEntryValue decimal.Decimal `json:"cum_entry_value"`
+	AvgEntryPrice decimal.Decimal `json:"avg_entry_price"`
+	CumExitValue  decimal.Decimal `json:"cum_exit_value"`
+	AvgExitPrice  decimal.Decimal `json:"avg_exit_price"`
+	ClosedPnl     decimal.Decimal `json:"closed_pnl"`
+	FillCount     int             `json:"fill_count"`
+	Leverage      decimal.Decimal `json:"leverage"`
+	// seconds
+	CreatedAt int64 `json:"created_at"`
+}
+
+type PerpClosedPnlResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		CurrentPage int                 `json:"current_page"`
+		Data        []PerpClosedPnlData `json:"data"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// every page between start and end merged into one response, newest first.
+// zero start / end and empty execType are left out.
+// past bybit's 50 page cap the window is narrowed to end at the oldest record read so far.
+// if one second holds more records than that, what was read comes back with ErrPageLimit.
+func (p *Client) PerpClosedPnl(symbol string, start, end time.Time, execType string) (result *PerpClosedPnlResponse, err error) {
+	return p.PerpClosedPnlContext(context.Background(), symbol, start, end, execType)
+}
+
+func (p *Client) PerpClosedPnlContext(ctx context.Context, symbol string, start, end time.Time, execType string) (result *PerpClosedPnlResponse, err error) {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	// in seconds for this endpoint
+	if !start.IsZero() {
+		params["start_time"] = strconv.FormatInt(start.Unix(), 10)
+	}
+	if !end.IsZero() {
+		params["end_time"] = strconv.FormatInt(end.Unix(), 10)
+	}
+	if execType != "" {
+		params["exec_type"] = execType
+	}
+	params["limit"] = strconv.Itoa(perpClosedPnlMaxLimit)
+	// ids already read at the current window's end
+	var skip map[int64]bool
+	for {
+		var oldest int64
+		var boundary map[int64]bool
+		for page := 1; page <= perpHistoryMaxPages; page++ {
+			params["page"] = strconv.Itoa(page)
+			pageResult, err := p.perpClosedPnlPage(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			data := pageResult.Result.Data
+			if result == nil {
+				result = pageResult
+				result.Result.Data = nil
+			}
+			result.Result.CurrentPage = pageResult.Result.CurrentPage
+			for _, item := range data {
+				if skip[item.ID] {
+					continue
+				}
+				result.Result.Data = append(result.Result.Data, item)
+				switch {
+				case boundary == nil || item.CreatedAt < oldest:
+					oldest = item.CreatedAt
+					boundary = map[int64]bool{item.ID: true}
+				case item.CreatedAt == oldest:
+					boundary[item.ID] = true
+				}
+			}
+			if len(data) < perpClosedPnlMaxLimit {
+				return result, nil
+			}
+		}
+		if windowEnd, ok := params["end_time"]; boundary == nil || ok && strconv.FormatInt(oldest, 10) == windowEnd {
+			return result, ErrPageLimit
+		}
+		params["end_time"] = strconv.FormatInt(oldest, 10)
+		skip = boundary
+	}
+}
+
+func (p *Client) perpClosedPnlPage(ctx context.Context, params map[string]string) (result *PerpClosedPnlResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/trade/closed-pnl/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package bybitapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+)
+
+func closedPnlRecords(n int, perSecond int) []map[string]interface{} {
+	base := time.Now().Unix()
+	records := make([]map[string]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		records = append(records, map[string]interface{}{
+			"id":         i + 1,
+			"symbol":     "BTCUSDT",
+			"closed_pnl": 1.5,
+			"created_at": base - int64(i/perSecond),
+		})
+	}
+	return records
+}
+
+func TestPerpClosedPnlPastPageCap(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithoutRateLimit())
+	records := closedPnlRecords(2600, 10)
+	srv.Handle(http.MethodGet, "/private/linear/trade/closed-pnl/list", bybittest.PagedList(records, "created_at", time.Second))
+
+	result, err := client.PerpClosedPnl("BTCUSDT", time.Time{}, time.Time{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int64]bool)
+	for _, item := range result.Result.Data {
+		if seen[item.ID] {
+			t.Fatalf("record %d returned twice", item.ID)
+		}
+		seen[item.ID] = true
+	}
+	if len(seen) != len(records) {
+		t.Errorf("got %d records, want %d", len(seen), len(records))
+	}
+}
+
+func TestPerpClosedPnlPageLimit(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithoutRateLimit())
+	// one second holds more than 50 pages
+	srv.Handle(http.MethodGet, "/private/linear/trade/closed-pnl/list", bybittest.PagedList(closedPnlRecords(2600, 2600), "created_at", time.Second))
+
+	result, err := client.PerpClosedPnl("BTCUSDT", time.Time{}, time.Time{}, "")
+	if !errors.Is(err, bybitapi.ErrPageLimit) {
+		t.Fatalf("err = %v, want ErrPageLimit", err)
+	}
+	if result == nil || len(result.Result.Data) != 2500 {
+		t.Errorf("partial result should keep the 2500 records read")
+	}
+}
@@ -0,0 +1,248 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const perpExecutionMaxLimit = 200
+
+type PerpExecutionFilter struct {
+	StartTime time.Time
+	EndTime   time.Time
+	// every type when empty, ex: ExecTypeTrade, ExecTypeFunding
+	ExecType string
+	// starts at 1
+	Page int
+	// 20 when zero, max 200
+	Limit int
+}
+
+func (f *PerpExecutionFilter) params(symbol string) map[string]string {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	if !f.StartTime.IsZero() {
+		params["start_time"] = strconv.FormatInt(f.StartTime.UnixMilli(), 10)
+	}
+	if !f.EndTime.IsZero() {
+		params["end_time"] = strconv.FormatInt(f.EndTime.UnixMilli(), 10)
+	}
+	if f.ExecType != "" {
+		params["exec_type"] = f.ExecType
+	}
+	if f.Page > 0 {
+		params["page"] = strconv.Itoa(f.Page)
+	}
+	if f.Limit > 0 {
+		params["limit"] = strconv.Itoa(f.Limit)
+	}
+	return params
+}
+
+type PerpExecutionData struct {
+	OrderID          string  `json:"order_id"`
+	OrderLinkID      string  `json:"order_link_id"`
+	Side             string  `json:"side"`
+	Symbol           string  `json:"symbol"`
+	ExecID           string  `json:"exec_id"`
+	OrderPrice       float64 `json:"order_price"`
+	OrderQty         float64 `json:"order_qty"`
+	OrderType        string  `json:"order_type"`
+	FeeRate          float64 `json:"fee_rate"`
+	ExecPrice        float64 `json:"exec_price"`
+	ExecType         string  `json:"exec_type"`
+	ExecQty          float64 `json:"exec_qty"`
+	ExecFee          float64 `json:"exec_fee"`
+	ExecValue        float64 `json:"exec_value"`
+	LeavesQty        float64 `json:"leaves_qty"`
+	ClosedSize       float64 `json:"closed_size"`
+	LastLiquidityInd string  `json:"last_liquidity_ind"`
+	TradeTime        int64   `json:"trade_time"`
+	TradeTimeMs      int64   `json:"trade_time_ms"`
+}
+
+// same shape as the fills of the execution topic, ReadPerpUserTrade
+func (d *PerpExecutionData) UserTrade() UserTradeData {
+	trade := UserTradeData{
+		Symbol:    d.Symbol,
+		Oid:       d.OrderID,
+		IsMaker:   d.LastLiquidityInd == "AddedLiquidity",
+		Price:     decimal.NewFromFloat(d.ExecPrice),
+		Qty:       decimal.NewFromFloat(d.ExecQty),
+		Fee:       decimal.NewFromFloat(d.ExecFee),
+		FeeAsset:  "USDT",
+		TimeStamp: time.UnixMilli(d.TradeTimeMs),
+	}
+	if strings.EqualFold(d.Side, "buy") {
+		trade.Side = UserTradeBuy
+	} else {
+		trade.Side = UserTradeSell
+	}
+	if trade.IsMaker {
+		trade.OrderType = "limit"
+	} else {
+		trade.OrderType = "market"
+	}
+	return trade
+}
+
+type PerpExecutionHistoryResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		CurrentPage int                 `json:"current_page"`
+		Data        []PerpExecutionData `json:"data"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// one page of fills, use PerpExecutionHistoryIter to walk all of them
+func (p *Client) PerpExecutionHistory(symbol string, filter PerpExecutionFilter) (result *PerpExecutionHistoryResponse, err error) {
+	return p.PerpExecutionHistoryContext(context.Background(), symbol, filter)
+}
+
+func (p *Client) PerpExecutionHistoryContext(ctx context.Context, symbol string, filter PerpExecutionFilter) (result *PerpExecutionHistoryResponse, err error) {
+	if filter.Limit > perpExecutionMaxLimit {
+		return nil, errors.New("limit is at most 200")
+	}
+	params := filter.params(symbol)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/trade/execution/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// bybit serves at most this many pages of one query on the linear history endpoints
+const perpHistoryMaxPages = 50
+
+// PerpExecutionHistoryIterator walks the pages until one comes back short, there is no last_page here.
+// When the page cap is reached the window is narrowed to end at the oldest fill seen so far
+// and paging starts over, fills on that boundary aren't returned twice.
+type PerpExecutionHistoryIterator struct {
+	client *Client
+	ctx    context.Context
+	symbol string
+	filter PerpExecutionFilter
+	page   []PerpExecutionData
+	idx    int
+	done   bool
+	err    error
+	// oldest trade_time_ms of the current window, and the exec ids at it
+	oldest   int64
+	boundary map[string]bool
+	// exec ids already returned at the current window's end
+	skip     map[string]bool
+	limitErr error
+}
+
+func (p *Client) PerpExecutionHistoryIter(symbol string, filter PerpExecutionFilter) *PerpExecutionHistoryIterator {
+	return p.PerpExecutionHistoryIterContext(context.Background(), symbol, filter)
+}
+
+// every page is requested with ctx
+func (p *Client) PerpExecutionHistoryIterContext(ctx context.Context, symbol string, filter PerpExecutionFilter) *PerpExecutionHistoryIterator {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = perpExecutionMaxLimit
+	}
+	return &PerpExecutionHistoryIterator{
+		client: p,
+		ctx:    ctx,
+		symbol: symbol,
+		filter: filter,
+		idx:    -1,
+	}
+}
+
+func (it *PerpExecutionHistoryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.idx++
+	for it.idx >= len(it.page) {
+		if it.done {
+			it.err = it.limitErr
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	return true
+}
+
+func (it *PerpExecutionHistoryIterator) fetch() error {
+	result, err := it.client.PerpExecutionHistoryContext(it.ctx, it.symbol, it.filter)
+	if err != nil {
+		return err
+	}
+	data := result.Result.Data
+	it.page = it.page[:0]
+	it.idx = 0
+	for _, exec := range data {
+		if it.skip[exec.ExecID] {
+			continue
+		}
+		it.page = append(it.page, exec)
+		switch {
+		case it.boundary == nil || exec.TradeTimeMs < it.oldest:
+			it.oldest = exec.TradeTimeMs
+			it.boundary = map[string]bool{exec.ExecID: true}
+		case exec.TradeTimeMs == it.oldest:
+			it.boundary[exec.ExecID] = true
+		}
+	}
+	if len(data) < it.filter.Limit {
+		it.done = true
+		return nil
+	}
+	if it.filter.Page < perpHistoryMaxPages {
+		it.filter.Page++
+		return nil
+	}
+	// out of pages, continue with a window ending at the oldest fill
+	end := time.UnixMilli(it.oldest)
+	if !it.filter.EndTime.IsZero() && !end.Before(it.filter.EndTime) {
+		// a single millisecond holds more fills than the pages can reach,
+		// reported once this page has been read
+		it.done = true
+		it.limitErr = ErrPageLimit
+		return nil
+	}
+	it.filter.EndTime = end
+	it.filter.Page = 1
+	it.skip = it.boundary
+	it.boundary = nil
+	return nil
+}
+
+// the fill Next moved to
+func (it *PerpExecutionHistoryIterator) Execution() PerpExecutionData {
+	return it.page[it.idx]
+}
+
+func (it *PerpExecutionHistoryIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,69 @@
+package bybitapi_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+)
+
+func TestPerpExecutionHistoryIterPastPageCap(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithoutRateLimit())
+
+	// 250 fills, two per millisecond, more than 50 pages of 2
+	base := time.Now().UnixMilli()
+	var fills []map[string]interface{}
+	for i := 0; i < 250; i++ {
+		fills = append(fills, map[string]interface{}{
+			"exec_id":       strconv.Itoa(i),
+			"symbol":        "BTCUSDT",
+			"trade_time_ms": base - int64(i/2),
+		})
+	}
+	srv.Handle(http.MethodGet, "/private/linear/trade/execution/list", bybittest.PagedList(fills, "trade_time_ms", time.Millisecond))
+
+	it := client.PerpExecutionHistoryIter("BTCUSDT", bybitapi.PerpExecutionFilter{Limit: 2})
+	seen := make(map[string]int)
+	for it.Next() {
+		seen[it.Execution().ExecID]++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(fills) {
+		t.Errorf("got %d fills, want %d", len(seen), len(fills))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("fill %s returned %d times", id, n)
+		}
+	}
+}
+
+func TestPerpExecutionHistoryIterPageLimit(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithoutRateLimit())
+
+	// every fill in the same millisecond, the window can't be narrowed
+	ts := time.Now().UnixMilli()
+	var fills []map[string]interface{}
+	for i := 0; i < 120; i++ {
+		fills = append(fills, map[string]interface{}{"exec_id": strconv.Itoa(i), "trade_time_ms": ts})
+	}
+	srv.Handle(http.MethodGet, "/private/linear/trade/execution/list", bybittest.PagedList(fills, "trade_time_ms", time.Millisecond))
+
+	it := client.PerpExecutionHistoryIter("BTCUSDT", bybitapi.PerpExecutionFilter{Limit: 2})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if !errors.Is(it.Err(), bybitapi.ErrPageLimit) {
+		t.Errorf("err = %v, want ErrPageLimit", it.Err())
+	}
+	if n != 100 {
+		t.Errorf("got %d fills before the error, want 100", n)
+	}
+}
@@ -0,0 +1,185 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const perpOrderListMaxLimit = 50
+
+type PerpOrderFilter struct {
+	OrderID     string
+	OrderLinkID string
+	// any status when empty, ex: PerpOrderStatusFilled, PerpOrderStatusCancelled
+	OrderStatus []string
+	// the endpoint has no time params, orders are dropped by created_time after fetching,
+	// so a page can hold fewer orders than Limit
+	StartTime time.Time
+	EndTime   time.Time
+	// starts at 1
+	Page int
+	// 20 when zero, max 50
+	Limit int
+}
+
+func (f *PerpOrderFilter) params(symbol string) map[string]string {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	if f.OrderID != "" {
+		params["order_id"] = f.OrderID
+	}
+	if f.OrderLinkID != "" {
+		params["order_link_id"] = f.OrderLinkID
+	}
+	if len(f.OrderStatus) != 0 {
+		params["order_status"] = strings.Join(f.OrderStatus, ",")
+	}
+	if f.Page > 0 {
+		params["page"] = strconv.Itoa(f.Page)
+	}
+	if f.Limit > 0 {
+		params["limit"] = strconv.Itoa(f.Limit)
+	}
+	return params
+}
+
+func (f *PerpOrderFilter) match(order PerpOrderData) bool {
+	if f.StartTime.IsZero() && f.EndTime.IsZero() {
+		return true
+	}
+	created, err := time.Parse(time.RFC3339, order.CreatedTime)
+	if err != nil {
+		return true
+	}
+	if !f.StartTime.IsZero() && created.Before(f.StartTime) {
+		return false
+	}
+	if !f.EndTime.IsZero() && created.After(f.EndTime) {
+		return false
+	}
+	return true
+}
+
+type PerpOrderHistoryResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		CurrentPage int             `json:"current_page"`
+		LastPage    int             `json:"last_page"`
+		Data        []PerpOrderData `json:"data"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          string      `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// one page of orders in any status, newest first, use PerpOrderHistoryIter to walk all of them
+func (p *Client) PerpOrderHistory(symbol string, filter PerpOrderFilter) (result *PerpOrderHistoryResponse, err error) {
+	return p.PerpOrderHistoryContext(context.Background(), symbol, filter)
+}
+
+func (p *Client) PerpOrderHistoryContext(ctx context.Context, symbol string, filter PerpOrderFilter) (result *PerpOrderHistoryResponse, err error) {
+	if filter.Limit > perpOrderListMaxLimit {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params(symbol)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/order/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	data := result.Result.Data[:0]
+	for _, order := range result.Result.Data {
+		if filter.match(order) {
+			data = append(data, order)
+		}
+	}
+	result.Result.Data = data
+	return result, nil
+}
+
+// PerpOrderHistoryIterator walks every page of the order list, starting at filter.Page.
+//
+//	it := client.PerpOrderHistoryIter("BTCUSDT", bybitapi.PerpOrderFilter{})
+//	for it.Next() {
+//		order := it.Order()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type PerpOrderHistoryIterator struct {
+	client *Client
+	ctx    context.Context
+	symbol string
+	filter PerpOrderFilter
+	page   []PerpOrderData
+	idx    int
+	done   bool
+	err    error
+}
+
+func (p *Client) PerpOrderHistoryIter(symbol string, filter PerpOrderFilter) *PerpOrderHistoryIterator {
+	return p.PerpOrderHistoryIterContext(context.Background(), symbol, filter)
+}
+
+// every page is requested with ctx
+func (p *Client) PerpOrderHistoryIterContext(ctx context.Context, symbol string, filter PerpOrderFilter) *PerpOrderHistoryIterator {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = perpOrderListMaxLimit
+	}
+	return &PerpOrderHistoryIterator{
+		client: p,
+		ctx:    ctx,
+		symbol: symbol,
+		filter: filter,
+		idx:    -1,
+	}
+}
+
+func (it *PerpOrderHistoryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.idx++
+	for it.idx >= len(it.page) {
+		if it.done {
+			return false
+		}
+		result, err := it.client.PerpOrderHistoryContext(it.ctx, it.symbol, it.filter)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = result.Result.Data
+		it.idx = 0
+		if result.Result.CurrentPage >= result.Result.LastPage {
+			it.done = true
+		}
+		it.filter.Page++
+	}
+	return true
+}
+
+// the order Next moved to
+func (it *PerpOrderHistoryIterator) Order() PerpOrderData {
+	return it.page[it.idx]
+}
+
+func (it *PerpOrderHistoryIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,106 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type PerpSymbolPositionsResponse struct {
+	RetCode          int                `json:"ret_code"`
+	RetMsg           string             `json:"ret_msg"`
+	ExtCode          string             `json:"ext_code"`
+	ExtInfo          string             `json:"ext_info"`
+	Result           []PerpPositionData `json:"result"`
+	TimeNow          string             `json:"time_now"`
+	RateLimitStatus  int                `json:"rate_limit_status"`
+	RateLimitResetMs int64              `json:"rate_limit_reset_ms"`
+	RateLimit        int                `json:"rate_limit"`
+}
+
+// both the Buy and the Sell position of one symbol, in either position mode
+func (p *Client) PerpPosition(symbol string) (result *PerpSymbolPositionsResponse, err error) {
+	return p.PerpPositionContext(context.Background(), symbol)
+}
+
+func (p *Client) PerpPositionContext(ctx context.Context, symbol string) (result *PerpSymbolPositionsResponse, err error) {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/position/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// position_idx an order needs to reach the position of positionSide (Buy for long, Sell for short)
+func PerpPositionIdx(mode, positionSide string) int {
+	if mode != PositionModeBothSide {
+		return PositionIdxOneWay
+	}
+	if positionSide == Sell {
+		return PositionIdxHedgeSell
+	}
+	return PositionIdxHedgeBuy
+}
+
+// symbol -> side (Buy / Sell) -> position
+type PerpPositionBook map[string]map[string]PerpPositionData
+
+func (b PerpPositionBook) add(pos PerpPositionData) {
+	sides, ok := b[pos.Symbol]
+	if !ok {
+		sides = make(map[string]PerpPositionData, 2)
+		b[pos.Symbol] = sides
+	}
+	sides[pos.Side] = pos
+}
+
+func (b PerpPositionBook) Get(symbol, side string) (PerpPositionData, bool) {
+	pos, ok := b[strings.ToUpper(symbol)][side]
+	return pos, ok
+}
+
+func (b PerpPositionBook) Long(symbol string) (PerpPositionData, bool) {
+	return b.Get(symbol, Buy)
+}
+
+func (b PerpPositionBook) Short(symbol string) (PerpPositionData, bool) {
+	return b.Get(symbol, Sell)
+}
+
+func (b PerpPositionBook) IsHedgeMode(symbol string) bool {
+	for _, pos := range b[strings.ToUpper(symbol)] {
+		if pos.Mode == PositionModeBothSide {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Client) LoadPerpPositionBook() (PerpPositionBook, error) {
+	return p.LoadPerpPositionBookContext(context.Background())
+}
+
+func (p *Client) LoadPerpPositionBookContext(ctx context.Context) (PerpPositionBook, error) {
+	result, err := p.PerpPositionsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	book := make(PerpPositionBook)
+	for _, item := range result.Result {
+		if !item.IsValid {
+			continue
+		}
+		book.add(item.Data)
+	}
+	return book, nil
+}
@@ -0,0 +1,394 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// stop_order_status
+const (
+	StopStatusUntriggered = "Untriggered"
+	StopStatusTriggered   = "Triggered"
+	StopStatusDeactivated = "Deactivated"
+	StopStatusActive      = "Active"
+	StopStatusCancelled   = "Cancelled"
+	StopStatusRejected    = "Rejected"
+)
+
+type PerpStopOrderDetail struct {
+	StopOrderID    string  `json:"stop_order_id"`
+	UserID         int     `json:"user_id"`
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"`
+	OrderType      string  `json:"order_type"`
+	Price          float64 `json:"price"`
+	Qty            float64 `json:"qty"`
+	TimeInForce    string  `json:"time_in_force"`
+	OrderStatus    string  `json:"order_status"`
+	TriggerPrice   float64 `json:"trigger_price"`
+	BasePrice      float64 `json:"base_price"`
+	TriggerBy      string  `json:"trigger_by"`
+	OrderLinkID    string  `json:"order_link_id"`
+	ReduceOnly     bool    `json:"reduce_only"`
+	CloseOnTrigger bool    `json:"close_on_trigger"`
+	TakeProfit     float64 `json:"take_profit"`
+	StopLoss       float64 `json:"stop_loss"`
+	TpTriggerBy    string  `json:"tp_trigger_by"`
+	SlTriggerBy    string  `json:"sl_trigger_by"`
+	PositionIdx    int     `json:"position_idx"`
+	CreatedTime    string  `json:"created_time"`
+	UpdatedTime    string  `json:"updated_time"`
+}
+
+type PerpPlaceStopOrderResponse struct {
+	RetCode          int                 `json:"ret_code"`
+	RetMsg           string              `json:"ret_msg"`
+	ExtCode          string              `json:"ext_code"`
+	ExtInfo          string              `json:"ext_info"`
+	Result           PerpStopOrderDetail `json:"result"`
+	TimeNow          string              `json:"time_now"`
+	RateLimitStatus  int                 `json:"rate_limit_status"`
+	RateLimitResetMs int64               `json:"rate_limit_reset_ms"`
+	RateLimit        int                 `json:"rate_limit"`
+}
+
+// Limit with a price is a stop limit, Market is a stop market order.
+// BasePrice is the current price, bybit uses it to tell whether TriggerPrice is above or below the market.
+type PerpStopOrderRequest struct {
+	PerpOrderRequest
+	TriggerPrice decimal.Decimal
+	BasePrice    decimal.Decimal
+	// LastPrice when empty, or MarkPrice, IndexPrice
+	TriggerBy string
+}
+
+func (r *PerpStopOrderRequest) validate() error {
+	if err := r.PerpOrderRequest.validate(); err != nil {
+		return err
+	}
+	if !r.TriggerPrice.IsPositive() || !r.BasePrice.IsPositive() {
+		return errors.New("conditional order needs a positive trigger price and base price")
+	}
+	return nil
+}
+
+func (r *PerpStopOrderRequest) params() map[string]interface{} {
+	params := r.PerpOrderRequest.params()
+	fstop, _ := r.TriggerPrice.Float64()
+	params["stop_px"] = fstop
+	fbase, _ := r.BasePrice.Float64()
+	params["base_price"] = fbase
+	params["trigger_by"] = LastPrice
+	if r.TriggerBy != "" {
+		params["trigger_by"] = r.TriggerBy
+	}
+	return params
+}
+
+func (p *Client) PerpPlaceStopOrder(req PerpStopOrderRequest) (result *PerpPlaceStopOrderResponse, err error) {
+	return p.PerpPlaceStopOrderContext(context.Background(), req)
+}
+
+func (p *Client) PerpPlaceStopOrderContext(ctx context.Context, req PerpStopOrderRequest) (result *PerpPlaceStopOrderResponse, err error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(req.params())
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/stop-order/create", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type PerpCancelStopOrderResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		StopOrderID string `json:"stop_order_id"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+func (p *Client) PerpCancelStopOrder(symbol, stopOrderID string) (result *PerpCancelStopOrderResponse, err error) {
+	return p.PerpCancelStopOrderContext(context.Background(), symbol, stopOrderID)
+}
+
+func (p *Client) PerpCancelStopOrderContext(ctx context.Context, symbol, stopOrderID string) (result *PerpCancelStopOrderResponse, err error) {
+	return p.perpCancelStopOrder(ctx, symbol, "stop_order_id", stopOrderID)
+}
+
+func (p *Client) PerpCancelStopOrderByLinkID(symbol, orderLinkID string) (result *PerpCancelStopOrderResponse, err error) {
+	return p.PerpCancelStopOrderByLinkIDContext(context.Background(), symbol, orderLinkID)
+}
+
+func (p *Client) PerpCancelStopOrderByLinkIDContext(ctx context.Context, symbol, orderLinkID string) (result *PerpCancelStopOrderResponse, err error) {
+	return p.perpCancelStopOrder(ctx, symbol, "order_link_id", orderLinkID)
+}
+
+func (p *Client) perpCancelStopOrder(ctx context.Context, symbol, idKey, id string) (result *PerpCancelStopOrderResponse, err error) {
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(symbol)
+	params[idKey] = id
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/stop-order/cancel", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type PerpCancelAllStopOrdersResponse struct {
+	RetCode          int      `json:"ret_code"`
+	RetMsg           string   `json:"ret_msg"`
+	ExtCode          string   `json:"ext_code"`
+	ExtInfo          string   `json:"ext_info"`
+	Result           []string `json:"result"`
+	TimeNow          string   `json:"time_now"`
+	RateLimitStatus  int      `json:"rate_limit_status"`
+	RateLimitResetMs int64    `json:"rate_limit_reset_ms"`
+	RateLimit        int      `json:"rate_limit"`
+}
+
+// this method will consume 10 requests, the client's rate limiter accounts for that
+func (p *Client) PerpCancelAllStopOrders(symbol string) (result *PerpCancelAllStopOrdersResponse, err error) {
+	return p.PerpCancelAllStopOrdersContext(context.Background(), symbol)
+}
+
+func (p *Client) PerpCancelAllStopOrdersContext(ctx context.Context, symbol string) (result *PerpCancelAllStopOrdersResponse, err error) {
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(symbol)
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/stop-order/cancel-all", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type PerpReplaceStopOrderResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		StopOrderID string `json:"stop_order_id"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// can replace price, qty and trigger price, if dont't want to replace any of them, pass 0
+func (p *Client) PerpReplaceStopOrder(symbol, stopOrderID string, price, qty, triggerPrice decimal.Decimal) (result *PerpReplaceStopOrderResponse, err error) {
+	return p.PerpReplaceStopOrderContext(context.Background(), symbol, stopOrderID, price, qty, triggerPrice)
+}
+
+func (p *Client) PerpReplaceStopOrderContext(ctx context.Context, symbol, stopOrderID string, price, qty, triggerPrice decimal.Decimal) (result *PerpReplaceStopOrderResponse, err error) {
+	return p.PerpAmendStopOrderContext(ctx, PerpAmendStopRequest{
+		Symbol:       symbol,
+		StopOrderID:  stopOrderID,
+		Price:        price,
+		Qty:          qty,
+		TriggerPrice: triggerPrice,
+	})
+}
+
+// same rules as PerpAmendRequest, plus a zero TriggerPrice is left unchanged
+type PerpAmendStopRequest struct {
+	Symbol       string
+	StopOrderID  string
+	OrderLinkID  string
+	Price        decimal.Decimal
+	Qty          decimal.Decimal
+	TriggerPrice decimal.Decimal
+	TakeProfit   *decimal.Decimal
+	StopLoss     *decimal.Decimal
+	TpTriggerBy  string
+	SlTriggerBy  string
+}
+
+func (r *PerpAmendStopRequest) params() (map[string]interface{}, error) {
+	if r.Symbol == "" {
+		return nil, errors.New("symbol is required")
+	}
+	if r.StopOrderID == "" && r.OrderLinkID == "" {
+		return nil, errors.New("stop order id or order link id is required")
+	}
+	if r.TriggerPrice.IsNegative() {
+		return nil, errors.New("trigger price can't be negative")
+	}
+	params := make(map[string]interface{})
+	params["symbol"] = strings.ToUpper(r.Symbol)
+	if r.StopOrderID != "" {
+		params["stop_order_id"] = r.StopOrderID
+	} else {
+		params["order_link_id"] = r.OrderLinkID
+	}
+	if !r.TriggerPrice.IsZero() {
+		ftrigger, _ := r.TriggerPrice.Float64()
+		params["p_r_trigger_price"] = ftrigger
+	}
+	if err := amendParams(params, r.Price, r.Qty, r.TakeProfit, r.StopLoss, r.TpTriggerBy, r.SlTriggerBy); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (p *Client) PerpAmendStopOrder(req PerpAmendStopRequest) (result *PerpReplaceStopOrderResponse, err error) {
+	return p.PerpAmendStopOrderContext(context.Background(), req)
+}
+
+func (p *Client) PerpAmendStopOrderContext(ctx context.Context, req PerpAmendStopRequest) (result *PerpReplaceStopOrderResponse, err error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 2 {
+		return nil, errors.New("nothing to amend")
+	}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/stop-order/replace", body, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type PerpGetStopOrdersResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		CurrentPage int                   `json:"current_page"`
+		LastPage    int                   `json:"last_page"`
+		Data        []PerpStopOrderDetail `json:"data"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          string      `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// status: StopStatusUntriggered, ... or empty for all, page starts at 1, limit max 50
+func (p *Client) PerpGetStopOrders(symbol, status string, page, limit int) (result *PerpGetStopOrdersResponse, err error) {
+	return p.PerpGetStopOrdersContext(context.Background(), symbol, status, page, limit)
+}
+
+func (p *Client) PerpGetStopOrdersContext(ctx context.Context, symbol, status string, page, limit int) (result *PerpGetStopOrdersResponse, err error) {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	if status != "" {
+		params["stop_order_status"] = status
+	}
+	if page > 0 {
+		params["page"] = strconv.Itoa(page)
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/stop-order/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type PerpGetStopOrderResponse struct {
+	RetCode          int                 `json:"ret_code"`
+	RetMsg           string              `json:"ret_msg"`
+	ExtCode          string              `json:"ext_code"`
+	ExtInfo          string              `json:"ext_info"`
+	Result           PerpStopOrderDetail `json:"result"`
+	TimeNow          string              `json:"time_now"`
+	RateLimitStatus  int                 `json:"rate_limit_status"`
+	RateLimitResetMs int64               `json:"rate_limit_reset_ms"`
+	RateLimit        int                 `json:"rate_limit"`
+}
+
+// real-time query, either stopOrderID or orderLinkID
+func (p *Client) PerpGetStopOrder(symbol, stopOrderID, orderLinkID string) (result *PerpGetStopOrderResponse, err error) {
+	return p.PerpGetStopOrderContext(context.Background(), symbol, stopOrderID, orderLinkID)
+}
+
+func (p *Client) PerpGetStopOrderContext(ctx context.Context, symbol, stopOrderID, orderLinkID string) (result *PerpGetStopOrderResponse, err error) {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(symbol)
+	if stopOrderID != "" {
+		params["stop_order_id"] = stopOrderID
+	}
+	if orderLinkID != "" {
+		params["order_link_id"] = orderLinkID
+	}
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/stop-order/search", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
@@ -1,8 +1,8 @@
 package bybitapi
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -45,28 +45,119 @@ type PerpPlaceOrderResult struct {
 	OrderLinkID    string  `json:"order_link_id"`
 	CreatedTime    string  `json:"created_time"`
 	UpdatedTime    string  `json:"updated_time"`
+	TakeProfit     float64 `json:"take_profit"`
+	StopLoss       float64 `json:"stop_loss"`
+	TpTriggerBy    string  `json:"tp_trigger_by"`
+	SlTriggerBy    string  `json:"sl_trigger_by"`
+	PositionIdx    int     `json:"position_idx"`
 }
 
 func (p *Client) PerpPlaceOrder(symbol, side, order_type string, price, qty decimal.Decimal, reduce_only bool) (result *PerpPlaceOrderResponse, err error) {
+	return p.PerpPlaceOrderContext(context.Background(), symbol, side, order_type, price, qty, reduce_only)
+}
+
+func (p *Client) PerpPlaceOrderContext(ctx context.Context, symbol, side, order_type string, price, qty decimal.Decimal, reduce_only bool) (result *PerpPlaceOrderResponse, err error) {
+	return p.PerpSubmitOrderContext(ctx, PerpOrderRequest{
+		Symbol:     symbol,
+		Side:       side,
+		OrderType:  order_type,
+		Price:      price,
+		Qty:        qty,
+		ReduceOnly: reduce_only,
+	})
+}
+
+// zero decimals and empty strings are left out of the request
+type PerpOrderRequest struct {
+	Symbol    string
+	Side      string
+	OrderType string
+	// ignored for Market
+	Price decimal.Decimal
+	Qty   decimal.Decimal
+	// GTC when empty, or IOC, FOK, PostOnly
+	TimeInForce    string
+	ReduceOnly     bool
+	CloseOnTrigger bool
+	OrderLinkID    string
+	TakeProfit     decimal.Decimal
+	StopLoss       decimal.Decimal
+	// LastPrice when empty, or MarkPrice, IndexPrice
+	TpTriggerBy string
+	SlTriggerBy string
+	// PositionIdxOneWay, or PositionIdxHedgeBuy / PositionIdxHedgeSell in BothSide mode
+	PositionIdx int
+}
+
+func (r *PerpOrderRequest) validate() error {
+	if r.Symbol == "" || r.Side == "" || r.OrderType == "" {
+		return errors.New("symbol, side and order type are required")
+	}
+	if !r.Qty.IsPositive() {
+		return errors.New("qty must be positive")
+	}
+	if r.OrderType == Limit && !r.Price.IsPositive() {
+		return errors.New("limit order needs a positive price")
+	}
+	if r.OrderType == Market && r.TimeInForce == PostOnly {
+		return errors.New("market order can't be PostOnly")
+	}
+	return nil
+}
+
+func (r *PerpOrderRequest) params() map[string]interface{} {
 	params := make(map[string]interface{})
-	params["symbol"] = strings.ToUpper(symbol)
-	params["side"] = side
-	switch order_type {
-	case Limit:
-		fprice, _ := price.Float64()
+	params["symbol"] = strings.ToUpper(r.Symbol)
+	params["side"] = r.Side
+	params["order_type"] = r.OrderType
+	if r.OrderType != Market {
+		fprice, _ := r.Price.Float64()
 		params["price"] = fprice
 	}
-	fqty, _ := qty.Float64()
+	fqty, _ := r.Qty.Float64()
 	params["qty"] = fqty
-	params["order_type"] = order_type
 	params["time_in_force"] = GTC
-	params["close_on_trigger"] = false
-	params["reduce_only"] = reduce_only
-	body, err := json.Marshal(params)
+	if r.TimeInForce != "" {
+		params["time_in_force"] = r.TimeInForce
+	}
+	params["reduce_only"] = r.ReduceOnly
+	params["close_on_trigger"] = r.CloseOnTrigger
+	if r.OrderLinkID != "" {
+		params["order_link_id"] = r.OrderLinkID
+	}
+	if !r.TakeProfit.IsZero() {
+		ftp, _ := r.TakeProfit.Float64()
+		params["take_profit"] = ftp
+	}
+	if !r.StopLoss.IsZero() {
+		fsl, _ := r.StopLoss.Float64()
+		params["stop_loss"] = fsl
+	}
+	if r.TpTriggerBy != "" {
+		params["tp_trigger_by"] = r.TpTriggerBy
+	}
+	if r.SlTriggerBy != "" {
+		params["sl_trigger_by"] = r.SlTriggerBy
+	}
+	if r.PositionIdx != PositionIdxOneWay {
+		params["position_idx"] = r.PositionIdx
+	}
+	return params
+}
+
+func (p *Client) PerpSubmitOrder(req PerpOrderRequest) (result *PerpPlaceOrderResponse, err error) {
+	return p.PerpSubmitOrderContext(context.Background(), req)
+}
+
+func (p *Client) PerpSubmitOrderContext(ctx context.Context, req PerpOrderRequest) (result *PerpPlaceOrderResponse, err error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(req.params())
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/order/create", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/order/create", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -77,10 +168,6 @@ func (p *Client) PerpPlaceOrder(symbol, side, order_type string, price, qty deci
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -116,12 +203,16 @@ type PerpGetOrderResponse struct {
 }
 
 func (p *Client) PerpGetOrder(symbol, oid string) (result *PerpGetOrderResponse, err error) {
+	return p.PerpGetOrderContext(context.Background(), symbol, oid)
+}
+
+func (p *Client) PerpGetOrderContext(ctx context.Context, symbol, oid string) (result *PerpGetOrderResponse, err error) {
 	params := make(map[string]string)
 	params["symbol"] = strings.ToUpper(symbol)
 	if oid != "" {
 		params["order_id"] = oid
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/private/linear/order/search", nil, &params, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/order/search", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -133,10 +224,6 @@ func (p *Client) PerpGetOrder(symbol, oid string) (result *PerpGetOrderResponse,
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -155,14 +242,30 @@ type PerpCancelOrderResponse struct {
 }
 
 func (p *Client) PerpCancelOrder(symbol, oid string) (result *PerpGetOrderResponse, err error) {
+	return p.PerpCancelOrderContext(context.Background(), symbol, oid)
+}
+
+func (p *Client) PerpCancelOrderContext(ctx context.Context, symbol, oid string) (result *PerpGetOrderResponse, err error) {
+	return p.perpCancelOrder(ctx, symbol, "order_id", oid)
+}
+
+func (p *Client) PerpCancelOrderByLinkID(symbol, orderLinkID string) (result *PerpGetOrderResponse, err error) {
+	return p.PerpCancelOrderByLinkIDContext(context.Background(), symbol, orderLinkID)
+}
+
+func (p *Client) PerpCancelOrderByLinkIDContext(ctx context.Context, symbol, orderLinkID string) (result *PerpGetOrderResponse, err error) {
+	return p.perpCancelOrder(ctx, symbol, "order_link_id", orderLinkID)
+}
+
+func (p *Client) perpCancelOrder(ctx context.Context, symbol, idKey, id string) (result *PerpGetOrderResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
-	params["order_id"] = oid
+	params[idKey] = id
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/order/cancel", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/order/cancel", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -174,10 +277,6 @@ func (p *Client) PerpCancelOrder(symbol, oid string) (result *PerpGetOrderRespon
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -196,22 +295,99 @@ type PerpReplaceOrderResponse struct {
 
 // can replace price and qty, if dont't want to replace any of them, pass 0
 func (p *Client) PerpReplaceOrder(symbol, oid string, price, qty decimal.Decimal) (result *PerpReplaceOrderResponse, err error) {
+	return p.PerpReplaceOrderContext(context.Background(), symbol, oid, price, qty)
+}
+
+func (p *Client) PerpReplaceOrderContext(ctx context.Context, symbol, oid string, price, qty decimal.Decimal) (result *PerpReplaceOrderResponse, err error) {
+	return p.PerpAmendOrderContext(ctx, PerpAmendRequest{
+		Symbol:  symbol,
+		OrderID: oid,
+		Price:   price,
+		Qty:     qty,
+	})
+}
+
+// address the order by OrderID or OrderLinkID.
+// zero Price / Qty and nil TakeProfit / StopLoss are left unchanged, a zero TakeProfit / StopLoss cancels it.
+type PerpAmendRequest struct {
+	Symbol      string
+	OrderID     string
+	OrderLinkID string
+	Price       decimal.Decimal
+	Qty         decimal.Decimal
+	TakeProfit  *decimal.Decimal
+	StopLoss    *decimal.Decimal
+	TpTriggerBy string
+	SlTriggerBy string
+}
+
+func (r *PerpAmendRequest) params() (map[string]interface{}, error) {
+	if r.Symbol == "" {
+		return nil, errors.New("symbol is required")
+	}
+	if r.OrderID == "" && r.OrderLinkID == "" {
+		return nil, errors.New("order id or order link id is required")
+	}
 	params := make(map[string]interface{})
-	params["symbol"] = strings.ToUpper(symbol)
-	params["order_id"] = oid
+	params["symbol"] = strings.ToUpper(r.Symbol)
+	if r.OrderID != "" {
+		params["order_id"] = r.OrderID
+	} else {
+		params["order_link_id"] = r.OrderLinkID
+	}
+	if err := amendParams(params, r.Price, r.Qty, r.TakeProfit, r.StopLoss, r.TpTriggerBy, r.SlTriggerBy); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// shared by active and conditional order amends
+func amendParams(params map[string]interface{}, price, qty decimal.Decimal, tp, sl *decimal.Decimal, tpBy, slBy string) error {
+	if price.IsNegative() || qty.IsNegative() {
+		return errors.New("price and qty can't be negative")
+	}
 	if !price.IsZero() {
 		fprice, _ := price.Float64()
 		params["p_r_price"] = fprice
 	}
-	if !price.IsZero() {
+	if !qty.IsZero() {
 		fqty, _ := qty.Float64()
 		params["p_r_qty"] = fqty
 	}
+	if tp != nil {
+		ftp, _ := tp.Float64()
+		params["take_profit"] = ftp
+	}
+	if sl != nil {
+		fsl, _ := sl.Float64()
+		params["stop_loss"] = fsl
+	}
+	if tpBy != "" {
+		params["tp_trigger_by"] = tpBy
+	}
+	if slBy != "" {
+		params["sl_trigger_by"] = slBy
+	}
+	return nil
+}
+
+func (p *Client) PerpAmendOrder(req PerpAmendRequest) (result *PerpReplaceOrderResponse, err error) {
+	return p.PerpAmendOrderContext(context.Background(), req)
+}
+
+func (p *Client) PerpAmendOrderContext(ctx context.Context, req PerpAmendRequest) (result *PerpReplaceOrderResponse, err error) {
+	params, err := req.params()
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 2 {
+		return nil, errors.New("nothing to amend")
+	}
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/order/create", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/order/replace", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -223,40 +399,38 @@ func (p *Client) PerpReplaceOrder(symbol, oid string, price, qty decimal.Decimal
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s", result.RetCode, result.RetMsg, result.ExtCode)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
+type PerpOrderData struct {
+	OrderID        string  `json:"order_id"`
+	UserID         int     `json:"user_id"`
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"`
+	OrderType      string  `json:"order_type"`
+	Price          float64 `json:"price"`
+	Qty            float64 `json:"qty"`
+	TimeInForce    string  `json:"time_in_force"`
+	OrderStatus    string  `json:"order_status"`
+	LastExecPrice  float64 `json:"last_exec_price"`
+	CumExecQty     float64 `json:"cum_exec_qty"`
+	CumExecValue   float64 `json:"cum_exec_value"`
+	CumExecFee     float64 `json:"cum_exec_fee"`
+	OrderLinkID    string  `json:"order_link_id"`
+	ReduceOnly     bool    `json:"reduce_only"`
+	CloseOnTrigger bool    `json:"close_on_trigger"`
+	CreatedTime    string  `json:"created_time"`
+	UpdatedTime    string  `json:"updated_time"`
+}
+
 type PerpGetAllOpenOrdersResponse struct {
 	RetCode int    `json:"ret_code"`
 	RetMsg  string `json:"ret_msg"`
 	ExtCode string `json:"ext_code"`
 	Result  struct {
-		CurrentPage int `json:"current_page"`
-		LastPage    int `json:"last_page"`
-		Data        []struct {
-			OrderID        string  `json:"order_id"`
-			UserID         int     `json:"user_id"`
-			Symbol         string  `json:"symbol"`
-			Side           string  `json:"side"`
-			OrderType      string  `json:"order_type"`
-			Price          float64 `json:"price"`
-			Qty            float64 `json:"qty"`
-			TimeInForce    string  `json:"time_in_force"`
-			OrderStatus    string  `json:"order_status"`
-			LastExecPrice  float64 `json:"last_exec_price"`
-			CumExecQty     float64 `json:"cum_exec_qty"`
-			CumExecValue   float64 `json:"cum_exec_value"`
-			CumExecFee     float64 `json:"cum_exec_fee"`
-			OrderLinkID    string  `json:"order_link_id"`
-			ReduceOnly     bool    `json:"reduce_only"`
-			CloseOnTrigger bool    `json:"close_on_trigger"`
-			CreatedTime    string  `json:"created_time"`
-			UpdatedTime    string  `json:"updated_time"`
-		} `json:"data"`
+		CurrentPage int             `json:"current_page"`
+		LastPage    int             `json:"last_page"`
+		Data        []PerpOrderData `json:"data"`
 	} `json:"result"`
 	ExtInfo          interface{} `json:"ext_info"`
 	TimeNow          string      `json:"time_now"`
@@ -265,26 +439,41 @@ type PerpGetAllOpenOrdersResponse struct {
 	RateLimit        int         `json:"rate_limit"`
 }
 
-// New / PartiallyFilled
+// New / PartiallyFilled, every page merged into one response
 func (p *Client) PerpGetAllOpenOrders(symbol string) (result *PerpGetAllOpenOrdersResponse, err error) {
-	params := make(map[string]string)
-	params["symbol"] = strings.ToUpper(symbol)
-	res, err := p.sendRequest(ProductPerp, http.MethodGet, "/private/linear/order/list", nil, &params, true)
-	if err != nil {
-		return nil, err
-	}
-	err = decode(res, &result)
-	if err != nil {
-		return nil, err
-	}
-	if result == nil {
-		return nil, errors.New("response is nil")
-	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
+	return p.PerpGetAllOpenOrdersContext(context.Background(), symbol)
+}
+
+func (p *Client) PerpGetAllOpenOrdersContext(ctx context.Context, symbol string) (result *PerpGetAllOpenOrdersResponse, err error) {
+	filter := PerpOrderFilter{
+		OrderStatus: []string{PerpOrderStatusNew, PerpOrderStatusPartiallyFilled},
+		Limit:       perpOrderListMaxLimit,
+	}
+	for page := 1; ; page++ {
+		filter.Page = page
+		params := filter.params(symbol)
+		res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/private/linear/order/list", nil, &params, true)
+		if err != nil {
+			return nil, err
+		}
+		var pageResult *PerpGetAllOpenOrdersResponse
+		err = decode(res, &pageResult)
+		if err != nil {
+			return nil, err
+		}
+		if pageResult == nil {
+			return nil, errors.New("response is nil")
+		}
+		if result == nil {
+			result = pageResult
+		} else {
+			result.Result.Data = append(result.Result.Data, pageResult.Result.Data...)
+			result.Result.CurrentPage = pageResult.Result.CurrentPage
+		}
+		if len(pageResult.Result.Data) == 0 || pageResult.Result.CurrentPage >= pageResult.Result.LastPage {
+			return result, nil
+		}
 	}
-	return result, nil
 }
 
 type PerpCancelAllOrdersResponse struct {
@@ -299,15 +488,19 @@ type PerpCancelAllOrdersResponse struct {
 	RateLimit        int      `json:"rate_limit"`
 }
 
-// this method will consume 10 requests, be careful
+// this method will consume 10 requests, the client's rate limiter accounts for that
 func (p *Client) PerpCancelAllOrders(symbol string) (result *PerpCancelAllOrdersResponse, err error) {
+	return p.PerpCancelAllOrdersContext(context.Background(), symbol)
+}
+
+func (p *Client) PerpCancelAllOrdersContext(ctx context.Context, symbol string) (result *PerpCancelAllOrdersResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = strings.ToUpper(symbol)
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/private/linear/order/cancel-all", body, nil, true)
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodPost, "/private/linear/order/cancel-all", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -319,9 +512,5 @@ func (p *Client) PerpCancelAllOrders(symbol string) (result *PerpCancelAllOrders
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
@@ -0,0 +1,204 @@
+package bybitapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	pathOrderCreate      = "/private/linear/order/create"
+	pathOrderReplace     = "/private/linear/order/replace"
+	pathStopOrderCreate  = "/private/linear/stop-order/create"
+	pathStopOrderReplace = "/private/linear/stop-order/replace"
+)
+
+func newTestClient(t *testing.T, opts ...bybitapi.ClientOption) (*bybittest.Server, *bybitapi.Client) {
+	t.Helper()
+	srv := bybittest.NewServer("key", "secret")
+	t.Cleanup(srv.Close)
+	opts = append([]bybitapi.ClientOption{bybitapi.WithEnvironment(srv.Environment())}, opts...)
+	return srv, bybitapi.New("key", "secret", "", opts...)
+}
+
+func lastRequest(t *testing.T, srv *bybittest.Server, method, spath string) bybittest.Request {
+	t.Helper()
+	req, ok := srv.LastRequest(method, spath)
+	if !ok {
+		t.Fatalf("no %s %s received", method, spath)
+	}
+	return req
+}
+
+func wantParams(t *testing.T, req bybittest.Request, want map[string]string, absent ...string) {
+	t.Helper()
+	for key, value := range want {
+		if got, ok := req.Params[key]; !ok || got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	for _, key := range absent {
+		if got, ok := req.Params[key]; ok {
+			t.Errorf("%s = %q, want it left out", key, got)
+		}
+	}
+}
+
+func TestPerpReplaceOrderUsesReplaceEndpoint(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	res, err := client.PerpReplaceOrder("btcusdt", "oid-1", decimal.NewFromInt(20000), decimal.NewFromFloat(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result.OrderID != "oid-1" {
+		t.Errorf("order id = %q, want oid-1", res.Result.OrderID)
+	}
+	if _, ok := srv.LastRequest(http.MethodPost, pathOrderCreate); ok {
+		t.Error("replace was sent to the create endpoint")
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathOrderReplace)
+	wantParams(t, req, map[string]string{
+		"symbol":    "BTCUSDT",
+		"order_id":  "oid-1",
+		"p_r_price": "20000",
+		"p_r_qty":   "0.5",
+	})
+}
+
+func TestPerpAmendOrderQtyOnly(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	if _, err := client.PerpReplaceOrder("BTCUSDT", "oid-1", decimal.Zero, decimal.NewFromFloat(0.2)); err != nil {
+		t.Fatal(err)
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathOrderReplace)
+	wantParams(t, req, map[string]string{"order_id": "oid-1", "p_r_qty": "0.2"}, "p_r_price")
+}
+
+func TestPerpAmendOrderByLinkID(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	zero := decimal.Zero
+	_, err := client.PerpAmendOrder(bybitapi.PerpAmendRequest{
+		Symbol:      "BTCUSDT",
+		OrderLinkID: "link-1",
+		Price:       decimal.NewFromInt(19000),
+		TakeProfit:  &zero,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathOrderReplace)
+	wantParams(t, req, map[string]string{
+		"order_link_id": "link-1",
+		"p_r_price":     "19000",
+		// a zero take profit cancels it
+		"take_profit": "0",
+	}, "order_id", "p_r_qty", "stop_loss")
+}
+
+func TestPerpAmendOrderErrors(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	tests := []struct {
+		name string
+		req  bybitapi.PerpAmendRequest
+		want string
+	}{
+		{"nothing to amend", bybitapi.PerpAmendRequest{Symbol: "BTCUSDT", OrderID: "oid-1"}, "nothing to amend"},
+		{"missing id", bybitapi.PerpAmendRequest{Symbol: "BTCUSDT", Qty: decimal.NewFromInt(1)}, "order id or order link id is required"},
+		{"missing symbol", bybitapi.PerpAmendRequest{OrderID: "oid-1", Qty: decimal.NewFromInt(1)}, "symbol is required"},
+		{"negative qty", bybitapi.PerpAmendRequest{Symbol: "BTCUSDT", OrderID: "oid-1", Qty: decimal.NewFromInt(-1)}, "price and qty can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.PerpAmendOrder(tt.req)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if _, ok := srv.LastRequest(http.MethodPost, pathOrderReplace); ok {
+		t.Error("an invalid amend reached the server")
+	}
+}
+
+func TestPerpReplaceStopOrderUsesReplaceEndpoint(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	res, err := client.PerpReplaceStopOrder("btcusdt", "soid-1", decimal.NewFromInt(20000), decimal.NewFromFloat(0.5), decimal.NewFromInt(19900))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result.StopOrderID != "soid-1" {
+		t.Errorf("stop order id = %q, want soid-1", res.Result.StopOrderID)
+	}
+	if _, ok := srv.LastRequest(http.MethodPost, pathStopOrderCreate); ok {
+		t.Error("replace was sent to the create endpoint")
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathStopOrderReplace)
+	wantParams(t, req, map[string]string{
+		"symbol":            "BTCUSDT",
+		"stop_order_id":     "soid-1",
+		"p_r_price":         "20000",
+		"p_r_qty":           "0.5",
+		"p_r_trigger_price": "19900",
+	})
+}
+
+func TestPerpAmendStopOrderQtyOnly(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	if _, err := client.PerpReplaceStopOrder("BTCUSDT", "soid-1", decimal.Zero, decimal.NewFromFloat(0.2), decimal.Zero); err != nil {
+		t.Fatal(err)
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathStopOrderReplace)
+	wantParams(t, req, map[string]string{"stop_order_id": "soid-1", "p_r_qty": "0.2"}, "p_r_price", "p_r_trigger_price")
+}
+
+func TestPerpAmendStopOrderByLinkID(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	_, err := client.PerpAmendStopOrder(bybitapi.PerpAmendStopRequest{
+		Symbol:       "BTCUSDT",
+		OrderLinkID:  "link-1",
+		TriggerPrice: decimal.NewFromInt(19500),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := lastRequest(t, srv, http.MethodPost, pathStopOrderReplace)
+	wantParams(t, req, map[string]string{
+		"order_link_id":     "link-1",
+		"p_r_trigger_price": "19500",
+	}, "stop_order_id", "p_r_price", "p_r_qty")
+}
+
+func TestPerpAmendStopOrderErrors(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	tests := []struct {
+		name string
+		req  bybitapi.PerpAmendStopRequest
+		want string
+	}{
+		{"nothing to amend", bybitapi.PerpAmendStopRequest{Symbol: "BTCUSDT", StopOrderID: "soid-1"}, "nothing to amend"},
+		{"missing id", bybitapi.PerpAmendStopRequest{Symbol: "BTCUSDT", Qty: decimal.NewFromInt(1)}, "stop order id or order link id is required"},
+		{"negative trigger", bybitapi.PerpAmendStopRequest{Symbol: "BTCUSDT", StopOrderID: "soid-1", TriggerPrice: decimal.NewFromInt(-1)}, "trigger price can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.PerpAmendStopOrder(tt.req)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if _, ok := srv.LastRequest(http.MethodPost, pathStopOrderReplace); ok {
+		t.Error("an invalid amend reached the server")
+	}
+}
@@ -0,0 +1,246 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimitMode int
+
+const (
+	// wait until the endpoint group has budget again, bounded by the max wait and the request's deadline
+	RateLimitBlock RateLimitMode = iota
+	// return ErrRateLimitExceeded instead of waiting
+	RateLimitFailFast
+)
+
+var ErrRateLimitExceeded = errors.New("bybit rate limit budget exhausted for this endpoint group")
+
+func WithRateLimitMode(mode RateLimitMode) ClientOption {
+	return func(c *Client) {
+		if c.limiter != nil {
+			c.limiter.mode = mode
+		}
+	}
+}
+
+// longest a request waits for budget in RateLimitBlock mode, unless changed by WithRateLimitMaxWait
+const DefaultRateLimitMaxWait = 3 * time.Second
+
+// a wait longer than maxWait, or past the request's deadline, fails with ErrRateLimitExceeded.
+// zero or less waits as long as the context allows.
+func WithRateLimitMaxWait(maxWait time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.limiter != nil {
+			c.limiter.maxWait = maxWait
+		}
+	}
+}
+
+// turn off client side throttling, bybit will still enforce its own limits
+func WithoutRateLimit() ClientOption {
+	return func(c *Client) {
+		c.limiter = nil
+	}
+}
+
+type rateLimitRule struct {
+	method string // empty for any method
+	prefix string
+	group  string
+	weight int
+}
+
+type rateLimitSeed struct {
+	limit  int
+	window time.Duration
+}
+
+// first match wins, paths without a rule are not throttled (public market data is ip limited)
+var rateLimitRules = []rateLimitRule{
+	{"", "/private/linear/order/cancel-all", "linear-order-cancel", 10},
+	{"", "/private/linear/order/cancel", "linear-order-cancel", 1},
+	{"", "/private/linear/order/list", "linear-order-query", 1},
+	{"", "/private/linear/order/search", "linear-order-query", 1},
+	{"", "/private/linear/order/", "linear-order", 1},
+	{"", "/private/linear/stop-order/cancel-all", "linear-stop-order-cancel", 10},
+	{"", "/private/linear/stop-order/cancel", "linear-stop-order-cancel", 1},
+	{"", "/private/linear/stop-order/list", "linear-order-query", 1},
+	{"", "/private/linear/stop-order/search", "linear-order-query", 1},
+	{"", "/private/linear/stop-order/", "linear-stop-order", 1},
+	{"", "/private/linear/position/list", "linear-position", 1},
+	{"", "/private/linear/position/", "linear-position-set", 1},
+	{"", "/private/linear/tpsl/", "linear-position-set", 1},
+	{"", "/private/linear/trade/", "linear-trade-history", 1},
+	{"", "/private/linear/funding/", "linear-funding", 1},
+	{"", "/v2/private/wallet/", "wallet", 1},
+	{"", "/v2/private/account/", "account", 1},
+	{http.MethodGet, "/spot/v1/", "spot-query", 1},
+	{"", "/spot/v1/order", "spot-order", 1},
+	{"", "/spot/order/", "spot-order", 1},
+	{"", "/asset/v1/private/", "asset", 1},
+}
+
+// documented defaults, replaced by whatever bybit reports in rate_limit
+var rateLimitSeeds = map[string]rateLimitSeed{
+	"linear-order":             {100, time.Minute},
+	"linear-order-cancel":      {100, time.Minute},
+	"linear-order-query":       {600, time.Minute},
+	"linear-stop-order":        {100, time.Minute},
+	"linear-stop-order-cancel": {100, time.Minute},
+	"linear-position":          {120, time.Minute},
+	"linear-position-set":      {75, time.Minute},
+	"linear-trade-history":     {120, time.Minute},
+	"linear-funding":           {120, time.Minute},
+	"wallet":                   {120, time.Minute},
+	"account":                  {600, time.Minute},
+	"spot-query":               {20, time.Second},
+	"spot-order":               {20, time.Second},
+	"asset":                    {60, time.Minute},
+}
+
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	window   time.Duration
+	last     time.Time
+	// bybit said the budget is gone until then
+	resetAt time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed > 0 {
+		b.tokens += b.capacity * float64(elapsed) / float64(b.window)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+// consumes n tokens and returns 0, or returns how long to wait before asking again
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if now.Before(b.resetAt) {
+		return b.resetAt.Sub(now)
+	}
+	if !b.resetAt.IsZero() {
+		b.tokens = b.capacity
+		b.last = now
+		b.resetAt = time.Time{}
+	}
+	b.refill(now)
+	if n > b.capacity {
+		n = b.capacity
+	}
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.capacity * float64(b.window))
+}
+
+type rateLimiter struct {
+	mux     sync.Mutex
+	mode    RateLimitMode
+	maxWait time.Duration
+	buckets map[string]*tokenBucket
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		maxWait: DefaultRateLimitMaxWait,
+		buckets: make(map[string]*tokenBucket, len(rateLimitSeeds)),
+	}
+}
+
+func matchRateLimitRule(method, spath string) (rule rateLimitRule, ok bool) {
+	for _, rule := range rateLimitRules {
+		if rule.method != "" && rule.method != method {
+			continue
+		}
+		if strings.HasPrefix(spath, rule.prefix) {
+			return rule, true
+		}
+	}
+	return rule, false
+}
+
+// call with mux held
+func (r *rateLimiter) bucket(group string, now time.Time) *tokenBucket {
+	if b, ok := r.buckets[group]; ok {
+		return b
+	}
+	seed := rateLimitSeeds[group]
+	b := &tokenBucket{
+		capacity: float64(seed.limit),
+		tokens:   float64(seed.limit),
+		window:   seed.window,
+		last:     now,
+	}
+	r.buckets[group] = b
+	return b
+}
+
+func (r *rateLimiter) wait(ctx context.Context, method, spath string) error {
+	rule, ok := matchRateLimitRule(method, spath)
+	if !ok {
+		return nil
+	}
+	// latest time the request may still go out
+	var limit time.Time
+	if r.maxWait > 0 {
+		limit = time.Now().Add(r.maxWait)
+	}
+	if deadline, ok := ctx.Deadline(); ok && (limit.IsZero() || deadline.Before(limit)) {
+		limit = deadline
+	}
+	for {
+		now := time.Now()
+		r.mux.Lock()
+		delay := r.bucket(rule.group, now).take(float64(rule.weight), now)
+		r.mux.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+		if r.mode == RateLimitFailFast {
+			return ErrRateLimitExceeded
+		}
+		if !limit.IsZero() && now.Add(delay).After(limit) {
+			return ErrRateLimitExceeded
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// bybit's own count wins over the local estimate, offset is the server clock minus the local one
+func (r *rateLimiter) update(method, spath string, env *retEnvelope, offset time.Duration) {
+	if env.RateLimit <= 0 {
+		return
+	}
+	rule, ok := matchRateLimitRule(method, spath)
+	if !ok {
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	now := time.Now()
+	b := r.bucket(rule.group, now)
+	b.refill(now)
+	b.capacity = float64(env.RateLimit)
+	b.tokens = float64(env.RateLimitStatus)
+	if env.RateLimitStatus <= 0 && env.RateLimitResetMs > 0 {
+		// the reset time is on bybit's clock, take is asked with local time
+		b.resetAt = time.UnixMilli(env.RateLimitResetMs).Add(-offset)
+	}
+}
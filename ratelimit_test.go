@@ -0,0 +1,136 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const pathTransfer = "/asset/v1/private/transfer"
+
+func TestRateLimitUpdateResetOnServerClock(t *testing.T) {
+	r := newRateLimiter()
+	// bybit's clock runs 30s ahead, the budget is back in a second of its time
+	offset := 30 * time.Second
+	r.update(http.MethodPost, pathTransfer, &retEnvelope{
+		RateLimit:        60,
+		RateLimitStatus:  0,
+		RateLimitResetMs: time.Now().Add(offset + time.Second).UnixMilli(),
+	}, offset)
+
+	delay := r.buckets["asset"].take(1, time.Now())
+	if delay <= 0 || delay > time.Second {
+		t.Errorf("delay = %v, want at most 1s", delay)
+	}
+}
+
+func TestTokenBucketTake(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{capacity: 10, tokens: 10, window: 10 * time.Second, last: now}
+
+	for i := 0; i < 10; i++ {
+		if delay := b.take(1, now); delay != 0 {
+			t.Fatalf("take %d: delay = %v, want 0", i, delay)
+		}
+	}
+	// one token per second
+	if delay := b.take(1, now); delay != time.Second {
+		t.Errorf("empty bucket: delay = %v, want 1s", delay)
+	}
+	if delay := b.take(3, now); delay != 3*time.Second {
+		t.Errorf("weight 3: delay = %v, want 3s", delay)
+	}
+	// heavier than the bucket waits for a full one
+	if delay := b.take(20, now); delay != 10*time.Second {
+		t.Errorf("weight 20: delay = %v, want 10s", delay)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{capacity: 10, tokens: 0, window: 10 * time.Second, last: now}
+
+	b.refill(now.Add(2500 * time.Millisecond))
+	if b.tokens != 2.5 {
+		t.Errorf("tokens = %v, want 2.5", b.tokens)
+	}
+	// a clock going backwards changes nothing
+	b.refill(now)
+	if b.tokens != 2.5 {
+		t.Errorf("tokens = %v after going back, want 2.5", b.tokens)
+	}
+	b.refill(now.Add(time.Hour))
+	if b.tokens != 10 {
+		t.Errorf("tokens = %v, want capped at 10", b.tokens)
+	}
+}
+
+func TestRateLimitUpdateOverridesBucket(t *testing.T) {
+	r := newRateLimiter()
+	r.update(http.MethodGet, "/private/linear/position/list", &retEnvelope{RateLimit: 50, RateLimitStatus: 7}, 0)
+	b := r.buckets["linear-position"]
+	if b.capacity != 50 || b.tokens < 7 || b.tokens > 7.01 {
+		t.Errorf("capacity = %v, tokens = %v, want 50 and 7", b.capacity, b.tokens)
+	}
+
+	// used up until the reset, then full again
+	reset := time.Now().Add(2 * time.Second)
+	r.update(http.MethodGet, "/private/linear/position/list", &retEnvelope{RateLimit: 50, RateLimitStatus: 0, RateLimitResetMs: reset.UnixMilli()}, 0)
+	if delay := b.take(1, time.Now()); delay <= time.Second || delay > 2*time.Second {
+		t.Errorf("before the reset: delay = %v, want up to 2s", delay)
+	}
+	if delay := b.take(1, reset.Add(time.Millisecond)); delay != 0 {
+		t.Errorf("after the reset: delay = %v, want 0", delay)
+	}
+	if b.tokens != 49 {
+		t.Errorf("tokens = %v after the reset, want 49", b.tokens)
+	}
+
+	// responses without rate_limit and paths without a rule are ignored
+	r.update(http.MethodGet, "/private/linear/position/list", &retEnvelope{}, 0)
+	r.update(http.MethodGet, "/v2/public/tickers", &retEnvelope{RateLimit: 1}, 0)
+	if b.capacity != 50 || len(r.buckets) != 1 {
+		t.Errorf("capacity = %v, buckets = %d", b.capacity, len(r.buckets))
+	}
+}
+
+// spot-query allows 20 per second, one token every 50ms
+func drainedLimiter(mode RateLimitMode, maxWait time.Duration) *rateLimiter {
+	r := newRateLimiter()
+	r.mode = mode
+	r.maxWait = maxWait
+	now := time.Now()
+	r.bucket("spot-query", now).tokens = 0
+	return r
+}
+
+func TestRateLimitWait(t *testing.T) {
+	const spath = "/spot/v1/account"
+	ctx := context.Background()
+
+	if err := drainedLimiter(RateLimitFailFast, 0).wait(ctx, http.MethodGet, spath); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("fail fast: err = %v, want ErrRateLimitExceeded", err)
+	}
+	if err := drainedLimiter(RateLimitBlock, 10*time.Millisecond).wait(ctx, http.MethodGet, spath); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("short max wait: err = %v, want ErrRateLimitExceeded", err)
+	}
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := drainedLimiter(RateLimitBlock, 0).wait(short, http.MethodGet, spath); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("short deadline: err = %v, want ErrRateLimitExceeded", err)
+	}
+
+	start := time.Now()
+	if err := drainedLimiter(RateLimitBlock, time.Second).wait(ctx, http.MethodGet, spath); err != nil {
+		t.Errorf("block: %v", err)
+	}
+	if waited := time.Since(start); waited < 40*time.Millisecond {
+		t.Errorf("block returned after %v, want about 50ms", waited)
+	}
+	// no rule, never throttled
+	if err := drainedLimiter(RateLimitFailFast, 0).wait(ctx, http.MethodGet, "/v2/public/tickers"); err != nil {
+		t.Errorf("public path: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy retries network errors, 5xx and rate limited responses.
+// Only GET requests and orders carrying an order_link_id / orderLinkId are retried,
+// anything else could be executed twice.
+type RetryPolicy struct {
+	// including the first try
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// called before sleeping for the next attempt
+	OnRetry func(RetryEvent)
+}
+
+type RetryEvent struct {
+	Method   string
+	Endpoint string
+	// the attempt that just failed, starts at 1
+	Attempt int
+	Delay   time.Duration
+	Err     error
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    3 * time.Second,
+}
+
+// retries are off unless this option is given
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.MaxAttempts <= 1 {
+			c.retry = nil
+			return
+		}
+		c.retry = &policy
+	}
+}
+
+func retryable(method string, body []byte, params *map[string]string) bool {
+	if method == http.MethodGet {
+		return true
+	}
+	if params != nil {
+		if (*params)["order_link_id"] != "" || (*params)["orderLinkId"] != "" {
+			return true
+		}
+	}
+	if len(body) != 0 {
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return false
+		}
+		for _, key := range []string{"order_link_id", "orderLinkId"} {
+			if id, ok := fields[key].(string); ok && id != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrRateLimitExceeded) {
+		return false
+	}
+	var e *APIError
+	if !errors.As(err, &e) {
+		// transport level failure
+		return true
+	}
+	return e.StatusCode >= http.StatusInternalServerError || IsRateLimited(err)
+}
+
+// exponential with jitter in [d/2, d]
+func (r *RetryPolicy) backoff(attempt int) time.Duration {
+	d := r.BaseDelay << uint(attempt-1)
+	if d <= 0 || (r.MaxDelay > 0 && d > r.MaxDelay) {
+		d = r.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func (r *RetryPolicy) do(ctx context.Context, method, spath string, send func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := send()
+		if err == nil || attempt >= r.MaxAttempts || !shouldRetry(ctx, err) {
+			return res, err
+		}
+		delay := r.backoff(attempt)
+		if r.OnRetry != nil {
+			r.OnRetry(RetryEvent{
+				Method:   method,
+				Endpoint: spath,
+				Attempt:  attempt,
+				Delay:    delay,
+				Err:      err,
+			})
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package bybitapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const pathAPIKey = "/v2/private/account/api-key"
+
+var testRetryPolicy = bybitapi.RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    40 * time.Millisecond,
+}
+
+// answers the first failures calls with status and fail, then with 200 and ok
+func failFirst(failures int, status int, fail, ok interface{}) bybittest.HandlerFunc {
+	calls := 0
+	return func(bybittest.Request) (int, interface{}) {
+		calls++
+		if calls <= failures {
+			return status, fail
+		}
+		return http.StatusOK, ok
+	}
+}
+
+func TestRetryGetOn5xx(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(testRetryPolicy))
+	srv.Handle(http.MethodGet, pathAPIKey, failFirst(2, http.StatusBadGateway, "bad gateway", bybittest.OK([]interface{}{})))
+
+	if _, err := client.ApiInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(srv, http.MethodGet, pathAPIKey); n != 3 {
+		t.Errorf("sent %d requests, want 3", n)
+	}
+}
+
+func TestRetryGetOnRateLimited(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(testRetryPolicy))
+	srv.Handle(http.MethodGet, pathAPIKey, failFirst(1, http.StatusOK, bybittest.Fail(10006, "too many visits"), bybittest.OK([]interface{}{})))
+
+	if _, err := client.ApiInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(srv, http.MethodGet, pathAPIKey); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
+
+func TestRetryPostWithoutLinkID(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(testRetryPolicy))
+	srv.Handle(http.MethodPost, pathOrderCreate, failFirst(1, http.StatusBadGateway, "bad gateway", bybittest.OK(map[string]interface{}{"order_id": "oid-1"})))
+
+	_, err := client.PerpSubmitOrder(bybitapi.PerpOrderRequest{
+		Symbol:    "BTCUSDT",
+		Side:      bybitapi.Buy,
+		OrderType: bybitapi.Limit,
+		Price:     decimal.NewFromInt(20000),
+		Qty:       decimal.NewFromFloat(0.01),
+	})
+	if err == nil {
+		t.Error("a failed order without order_link_id was retried")
+	}
+	if n := countRequests(srv, http.MethodPost, pathOrderCreate); n != 1 {
+		t.Errorf("sent %d orders, want 1", n)
+	}
+}
+
+func TestRetryPostWithLinkID(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(testRetryPolicy))
+	srv.Handle(http.MethodPost, pathOrderCreate, failFirst(1, http.StatusBadGateway, "bad gateway", bybittest.OK(map[string]interface{}{"order_id": "oid-1"})))
+
+	_, err := client.PerpSubmitOrder(bybitapi.PerpOrderRequest{
+		Symbol:      "BTCUSDT",
+		Side:        bybitapi.Buy,
+		OrderType:   bybitapi.Limit,
+		Price:       decimal.NewFromInt(20000),
+		Qty:         decimal.NewFromFloat(0.01),
+		OrderLinkID: "link-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(srv, http.MethodPost, pathOrderCreate); n != 2 {
+		t.Errorf("sent %d orders, want 2", n)
+	}
+}
+
+func TestRetryOnRetryEvents(t *testing.T) {
+	var events []bybitapi.RetryEvent
+	policy := testRetryPolicy
+	policy.OnRetry = func(e bybitapi.RetryEvent) {
+		events = append(events, e)
+	}
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(policy))
+	srv.Respond(http.MethodGet, pathAPIKey, bybittest.Fail(10006, "too many visits"))
+
+	if _, err := client.ApiInfo(); !bybitapi.IsRateLimited(err) {
+		t.Fatalf("err = %v, want the rate limited error", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d retry events, want 2", len(events))
+	}
+	for i, e := range events {
+		// BaseDelay doubled per attempt, jittered down to half, capped by MaxDelay
+		max := policy.BaseDelay << uint(i)
+		if max > policy.MaxDelay {
+			max = policy.MaxDelay
+		}
+		if e.Attempt != i+1 || e.Method != http.MethodGet || e.Endpoint != pathAPIKey || e.Err == nil {
+			t.Errorf("event %d = %+v", i, e)
+		}
+		if e.Delay < max/2 || e.Delay > max {
+			t.Errorf("event %d delay = %v, want in [%v, %v]", i, e.Delay, max/2, max)
+		}
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	policy := testRetryPolicy
+	policy.MaxAttempts = 5
+	policy.BaseDelay = time.Second
+	policy.MaxDelay = time.Second
+	policy.OnRetry = func(bybitapi.RetryEvent) {
+		cancel()
+	}
+	srv, client := newTestClient(t, bybitapi.WithRetryPolicy(policy))
+	srv.Respond(http.MethodGet, pathAPIKey, bybittest.Fail(10006, "too many visits"))
+
+	start := time.Now()
+	if _, err := client.ApiInfoContext(ctx); err == nil {
+		t.Error("cancelled retry returned no error")
+	}
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Errorf("returned after %v, want right after the cancel", waited)
+	}
+	if n := countRequests(srv, http.MethodGet, pathAPIKey); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
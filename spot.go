@@ -1,8 +1,8 @@
 package bybitapi
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
 )
 
@@ -24,7 +24,11 @@ type GetSpotWalletBalanceResponse struct {
 }
 
 func (p *Client) GetSpotWalletBalance() (result *GetSpotWalletBalanceResponse, err error) {
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/v1/account", nil, nil, true)
+	return p.GetSpotWalletBalanceContext(context.Background())
+}
+
+func (p *Client) GetSpotWalletBalanceContext(ctx context.Context) (result *GetSpotWalletBalanceResponse, err error) {
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/v1/account", nil, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -35,10 +39,6 @@ func (p *Client) GetSpotWalletBalance() (result *GetSpotWalletBalanceResponse, e
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -53,7 +53,11 @@ type GetSpotServerTimeResponse struct {
 }
 
 func (p *Client) GetSpotServerTime() (result *GetSpotServerTimeResponse, err error) {
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/v1/time", nil, nil, false)
+	return p.GetSpotServerTimeContext(context.Background())
+}
+
+func (p *Client) GetSpotServerTimeContext(ctx context.Context) (result *GetSpotServerTimeResponse, err error) {
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/v1/time", nil, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -64,10 +68,6 @@ func (p *Client) GetSpotServerTime() (result *GetSpotServerTimeResponse, err err
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -93,7 +93,11 @@ type SpotsInfoResponse struct {
 }
 
 func (p *Client) SpotsInfo() (resp *SpotsInfoResponse, err error) {
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/v1/symbols", nil, nil, false)
+	return p.SpotsInfoContext(context.Background())
+}
+
+func (p *Client) SpotsInfoContext(ctx context.Context) (resp *SpotsInfoResponse, err error) {
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/v1/symbols", nil, nil, false)
 	if err != nil {
 		return nil, err
 	}
@@ -105,9 +109,5 @@ func (p *Client) SpotsInfo() (resp *SpotsInfoResponse, err error) {
 	if resp == nil {
 		return nil, errors.New("response is nil")
 	}
-	if resp.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", resp.ExtCode, resp.RetMsg, resp.ExtCode, resp.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return resp, nil
 }
@@ -23,6 +23,8 @@ type spotPrivateChannelBranch struct {
 	secret     string
 	subaccount string
 	product    string
+	env        Environment
+	clock      *serverClock
 	tradeSets  tradeDataMap
 	logger     *logrus.Logger
 }
@@ -95,6 +97,8 @@ func (c *Client) spotPrivateChannelStream(logger *logrus.Logger) {
 	o.key = c.key
 	o.secret = c.secret
 	o.subaccount = c.subaccount
+	o.env = c.env
+	o.clock = c.clock
 	o.product = ProductSpot
 	o.tradeSets.set = make(map[string][]UserTradeData, 5)
 	o.logger = logger
@@ -136,16 +140,17 @@ func (o *spotPrivateChannelBranch) maintain(ctx context.Context) error {
 	var duration time.Duration = 300
 	var w ws
 	innerErr := make(chan error, 1)
-	url := "wss://stream.bybit.com/spot/ws"
+	url := o.env.streamURL("/spot/ws")
 	// wait 5 second, if the hand shake fail, will terminate the dail
-	dailCtx, _ := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
+	dailCtx, dailCancel := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
 	conn, _, err := websocket.DefaultDialer.DialContext(dailCtx, url, nil)
+	dailCancel()
 	if err != nil {
 		return err
 	}
 	w.conn = conn
 	defer w.conn.Close()
-	if err := w.getAuth(o.key, o.secret); err != nil {
+	if err := w.getAuth(o.key, o.secret, o.clock.now()); err != nil {
 		return err
 	}
 	if err := w.conn.SetReadDeadline(time.Now().Add(time.Second * duration)); err != nil {
@@ -198,9 +203,10 @@ func (o *spotPrivateChannelBranch) maintain(ctx context.Context) error {
 }
 
 // official github
-func (w *ws) getAuth(key, secret string) error {
+// now is the server aligned time
+func (w *ws) getAuth(key, secret string, now time.Time) error {
 	//generate signature
-	expires := fmt.Sprintf("%v", time.Now().Unix()) + "1000"
+	expires := fmt.Sprintf("%v", now.Unix()) + "1000"
 	h := hmac.New(sha256.New, []byte(secret))
 	_val := "GET/realtime" + expires
 	io.WriteString(h, _val)
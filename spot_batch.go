@@ -0,0 +1,25 @@
+package bybitapi
+
+import "context"
+
+type SpotBatchPlaceResult struct {
+	Response *SpotPlaceOrderResponse
+	Err      error
+}
+
+// spot v1 has no batch create endpoint, orders are sent as single requests by the
+// batch workers (WithBatchConcurrency) and paced by the client's rate limiter.
+// results are in the order of reqs
+func (p *Client) SpotBatchPlaceOrders(reqs []SpotOrderRequest) []SpotBatchPlaceResult {
+	return p.SpotBatchPlaceOrdersContext(context.Background(), reqs)
+}
+
+func (p *Client) SpotBatchPlaceOrdersContext(ctx context.Context, reqs []SpotOrderRequest) []SpotBatchPlaceResult {
+	results := make([]SpotBatchPlaceResult, len(reqs))
+	runBatch(ctx, len(reqs), p.batchConcurrency, func(i int) {
+		results[i].Response, results[i].Err = p.SpotSubmitOrderContext(ctx, reqs[i])
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
@@ -0,0 +1,142 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SpotMyTradesRequest struct {
+	Symbol string
+	// trade ids, only trades after FromID / before ToID are returned
+	FromID string
+	ToID   string
+	// max 50
+	Limit     int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func (r *SpotMyTradesRequest) params() map[string]string {
+	params := make(map[string]string)
+	if r.Symbol != "" {
+		params["symbol"] = strings.ToUpper(r.Symbol)
+	}
+	if r.FromID != "" {
+		params["fromId"] = r.FromID
+	}
+	if r.ToID != "" {
+		params["toId"] = r.ToID
+	}
+	if r.Limit > 0 {
+		params["limit"] = strconv.Itoa(r.Limit)
+	}
+	if !r.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(r.StartTime.UnixMilli(), 10)
+	}
+	if !r.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(r.EndTime.UnixMilli(), 10)
+	}
+	return params
+}
+
+type SpotTradeData struct {
+	ID              string `json:"id"`
+	Symbol          string `json:"symbol"`
+	SymbolName      string `json:"symbolName"`
+	OrderID         string `json:"orderId"`
+	TicketID        string `json:"ticketId"`
+	MatchOrderID    string `json:"matchOrderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            string `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+	Fee             struct {
+		FeeTokenID   string `json:"feeTokenId"`
+		FeeTokenName string `json:"feeTokenName"`
+		Fee          string `json:"fee"`
+	} `json:"fee"`
+	FeeTokenID    string `json:"feeTokenId"`
+	FeeAmount     string `json:"feeAmount"`
+	MakerRebate   string `json:"makerRebate"`
+	ExecutionTime string `json:"executionTime"`
+}
+
+// same shape as the fills of the private stream, OrderType is left empty
+// because the endpoint doesn't return it
+func (d *SpotTradeData) UserTrade() UserTradeData {
+	trade := UserTradeData{
+		Symbol:   d.Symbol,
+		Oid:      d.OrderID,
+		IsMaker:  d.IsMaker,
+		FeeAsset: d.CommissionAsset,
+	}
+	trade.Price, _ = decimal.NewFromString(d.Price)
+	trade.Qty, _ = decimal.NewFromString(d.Qty)
+	trade.Fee, _ = decimal.NewFromString(d.Commission)
+	if trade.FeeAsset == "" {
+		trade.FeeAsset = d.Fee.FeeTokenName
+		trade.Fee, _ = decimal.NewFromString(d.Fee.Fee)
+	}
+	if d.IsBuyer {
+		trade.Side = UserTradeBuy
+	} else {
+		trade.Side = UserTradeSell
+	}
+	ts := d.ExecutionTime
+	if ts == "" {
+		ts = d.Time
+	}
+	if ms, err := strconv.ParseInt(ts, 10, 64); err == nil {
+		trade.TimeStamp = time.UnixMilli(ms)
+	}
+	return trade
+}
+
+type SpotMyTradesResponse struct {
+	RetCode int             `json:"ret_code"`
+	RetMsg  string          `json:"ret_msg"`
+	ExtCode interface{}     `json:"ext_code"`
+	ExtInfo interface{}     `json:"ext_info"`
+	Result  []SpotTradeData `json:"result"`
+}
+
+func (r *SpotMyTradesResponse) UserTrades() []UserTradeData {
+	trades := make([]UserTradeData, 0, len(r.Result))
+	for i := range r.Result {
+		trades = append(trades, r.Result[i].UserTrade())
+	}
+	return trades
+}
+
+func (p *Client) SpotMyTrades(req SpotMyTradesRequest) (result *SpotMyTradesResponse, err error) {
+	return p.SpotMyTradesContext(context.Background(), req)
+}
+
+func (p *Client) SpotMyTradesContext(ctx context.Context, req SpotMyTradesRequest) (result *SpotMyTradesResponse, err error) {
+	if req.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := req.params()
+	res, err := p.sendRequest(ctx, ProductSpot, http.MethodGet, "/spot/v1/myTrades", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	// in Close()
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
@@ -0,0 +1,148 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SpotOrderHistoryResponse struct {
+	RetCode int             `json:"ret_code"`
+	RetMsg  string          `json:"ret_msg"`
+	ExtCode interface{}     `json:"ext_code"`
+	ExtInfo interface{}     `json:"ext_info"`
+	Result  []SpotOrderData `json:"result"`
+}
+
+// filled and cancelled orders, newest first
+type SpotOrderHistoryRequest struct {
+	// all symbols when empty
+	Symbol string
+	// cursor, only orders older than this id are returned
+	OrderID string
+	// 50 when zero, max 500
+	Limit     int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func (r *SpotOrderHistoryRequest) params() map[string]string {
+	params := make(map[string]string)
+	if r.Symbol != "" {
+		params["symbol"] = strings.ToUpper(r.Symbol)
+	}
+	if r.OrderID != "" {
+		params["orderId"] = r.OrderID
+	}
+	if r.Limit > 0 {
+		params["limit"] = strconv.Itoa(r.Limit)
+	}
+	if !r.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(r.StartTime.UnixMilli(), 10)
+	}
+	if !r.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(r.EndTime.UnixMilli(), 10)
+	}
+	return params
+}
+
+// one page, use SpotOrderHistoryIter to walk all of them
+func (p *Client) SpotOrderHistory(req SpotOrderHistoryRequest) (result *SpotOrderHistoryResponse, err error) {
+	return p.SpotOrderHistoryContext(context.Background(), req)
+}
+
+func (p *Client) SpotOrderHistoryContext(ctx context.Context, req SpotOrderHistoryRequest) (result *SpotOrderHistoryResponse, err error) {
+	if req.Limit > 500 {
+		return nil, errors.New("limit is at most 500")
+	}
+	params := req.params()
+	res, err := p.sendRequest(ctx, ProductSpot, http.MethodGet, "/spot/v1/history-orders", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	// in Close()
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// SpotOrderHistoryIterator pages through the order history with the orderId cursor.
+//
+//	it := client.SpotOrderHistoryIter(bybitapi.SpotOrderHistoryRequest{Symbol: "BTCUSDT"})
+//	for it.Next() {
+//		order := it.Order()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type SpotOrderHistoryIterator struct {
+	client *Client
+	ctx    context.Context
+	req    SpotOrderHistoryRequest
+	page   []SpotOrderData
+	idx    int
+	done   bool
+	err    error
+}
+
+func (p *Client) SpotOrderHistoryIter(req SpotOrderHistoryRequest) *SpotOrderHistoryIterator {
+	return p.SpotOrderHistoryIterContext(context.Background(), req)
+}
+
+// every page is requested with ctx
+func (p *Client) SpotOrderHistoryIterContext(ctx context.Context, req SpotOrderHistoryRequest) *SpotOrderHistoryIterator {
+	return &SpotOrderHistoryIterator{
+		client: p,
+		ctx:    ctx,
+		req:    req,
+		idx:    -1,
+	}
+}
+
+func (it *SpotOrderHistoryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.idx++
+	if it.idx < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+	result, err := it.client.SpotOrderHistoryContext(it.ctx, it.req)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = result.Result
+	it.idx = 0
+	limit := it.req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if len(it.page) < limit {
+		it.done = true
+	}
+	if len(it.page) == 0 {
+		return false
+	}
+	it.req.OrderID = it.page[len(it.page)-1].Orderid
+	return true
+}
+
+// the order Next moved to
+func (it *SpotOrderHistoryIterator) Order() SpotOrderData {
+	return it.page[it.idx]
+}
+
+func (it *SpotOrderHistoryIterator) Err() error {
+	return it.err
+}
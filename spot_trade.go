@@ -1,6 +1,7 @@
 package bybitapi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -32,22 +33,111 @@ type SpotPlaceOrderResponse struct {
 }
 
 // ex: "BTCUSDT"
-// order_type: SpotLimit, SpotMarket, SpotLimitMaker
-// if it's SpotMarket, qty is in quote asset, be careful
+// order_type: SpotLimit, SpotMARKET, SpotLimitMaker
+// if it's SpotMARKET buy, qty is in quote asset, be careful
 func (p *Client) SpotPlaceOrder(symbol, side, order_type string, price, qty decimal.Decimal) (result *SpotPlaceOrderResponse, err error) {
-	params := make(map[string]string)
-	params["symbol"] = strings.ToUpper(symbol)
-	params["side"] = side
-	switch order_type {
-	case Market:
-		// pass
+	return p.SpotPlaceOrderContext(context.Background(), symbol, side, order_type, price, qty)
+}
+
+func (p *Client) SpotPlaceOrderContext(ctx context.Context, symbol, side, order_type string, price, qty decimal.Decimal) (result *SpotPlaceOrderResponse, err error) {
+	req := SpotOrderRequest{
+		Symbol: symbol,
+		Side:   side,
+		Type:   order_type,
+		Qty:    qty,
+	}
+	if order_type != SpotMARKET {
+		req.Price = price
+	}
+	return p.SpotSubmitOrderContext(ctx, req)
+}
+
+type SpotOrderRequest struct {
+	Symbol string
+	Side   string
+	// SpotLimit, SpotMARKET, SpotLimitMaker
+	Type string
+	// must be zero for SpotMARKET
+	Price decimal.Decimal
+	// in quote asset for a SpotMARKET buy
+	Qty decimal.Decimal
+	// SpotGTC when empty, SpotFOK and SpotIOC only for SpotLimit
+	TimeInForce string
+	OrderLinkID string
+}
+
+func (r *SpotOrderRequest) validate() error {
+	if r.Symbol == "" {
+		return errors.New("symbol is required")
+	}
+	if !strings.EqualFold(r.Side, Buy) && !strings.EqualFold(r.Side, Sell) {
+		return fmt.Errorf("unknown side %q", r.Side)
+	}
+	if !r.Qty.IsPositive() {
+		return errors.New("qty must be positive")
+	}
+	switch r.TimeInForce {
+	case "", SpotGTC, SpotFOK, SpotIOC:
 	default:
-		params["price"] = price.String()
+		return fmt.Errorf("unknown time in force %q", r.TimeInForce)
+	}
+	switch r.Type {
+	case SpotLimit:
+		if !r.Price.IsPositive() {
+			return errors.New("limit order needs a positive price")
+		}
+	case SpotLimitMaker:
+		if !r.Price.IsPositive() {
+			return errors.New("limit maker order needs a positive price")
+		}
+		if r.TimeInForce == SpotFOK || r.TimeInForce == SpotIOC {
+			return fmt.Errorf("limit maker order can't be %s", r.TimeInForce)
+		}
+	case SpotMARKET:
+		if !r.Price.IsZero() {
+			return errors.New("market order can't carry a price")
+		}
+		if r.TimeInForce == SpotFOK {
+			return errors.New("market order can't be FOK")
+		}
+	default:
+		return fmt.Errorf("unknown order type %q", r.Type)
+	}
+	if len(r.OrderLinkID) > 36 {
+		return errors.New("orderLinkId is limited to 36 characters")
+	}
+	return nil
+}
+
+func (r *SpotOrderRequest) params() map[string]string {
+	params := make(map[string]string)
+	params["symbol"] = strings.ToUpper(r.Symbol)
+	params["side"] = r.Side
+	params["type"] = r.Type
+	params["qty"] = r.Qty.String()
+	if r.Type != SpotMARKET {
+		params["price"] = r.Price.String()
+	}
+	params["timeInForce"] = SpotGTC
+	if r.TimeInForce != "" {
+		params["timeInForce"] = r.TimeInForce
 	}
-	params["qty"] = qty.String()
-	params["type"] = order_type
-	params["time_in_force"] = "GTC"
-	res, err := p.sendRequest("spot", http.MethodPost, "/spot/v1/order", nil, &params, true)
+	if r.OrderLinkID != "" {
+		params["orderLinkId"] = r.OrderLinkID
+	}
+	return params
+}
+
+func (p *Client) SpotSubmitOrder(req SpotOrderRequest) (result *SpotPlaceOrderResponse, err error) {
+	return p.SpotSubmitOrderContext(context.Background(), req)
+}
+
+func (p *Client) SpotSubmitOrderContext(ctx context.Context, req SpotOrderRequest) (result *SpotPlaceOrderResponse, err error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	params := req.params()
+	res, err := p.sendRequest(ctx, ProductSpot, http.MethodPost, "/spot/v1/order", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -59,10 +149,6 @@ func (p *Client) SpotPlaceOrder(symbol, side, order_type string, price, qty deci
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -88,9 +174,13 @@ type SpotCancelOrderResponse struct {
 }
 
 func (p *Client) SpotCancelOrder(oid string) (result *SpotCancelOrderResponse, err error) {
+	return p.SpotCancelOrderContext(context.Background(), oid)
+}
+
+func (p *Client) SpotCancelOrderContext(ctx context.Context, oid string) (result *SpotCancelOrderResponse, err error) {
 	params := make(map[string]string)
 	params["orderId"] = oid
-	res, err := p.sendRequest("spot", http.MethodDelete, "/spot/v1/order", nil, &params, true)
+	res, err := p.sendRequest(ctx, "spot", http.MethodDelete, "/spot/v1/order", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -102,10 +192,6 @@ func (p *Client) SpotCancelOrder(oid string) (result *SpotCancelOrderResponse, e
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -121,13 +207,17 @@ type SpotCancelAllOrdersResponse struct {
 
 // cancel all orders for given symbol
 func (p *Client) SpotCancelAllOrders(symbol string) (result *SpotCancelOrderResponse, err error) {
+	return p.SpotCancelAllOrdersContext(context.Background(), symbol)
+}
+
+func (p *Client) SpotCancelAllOrdersContext(ctx context.Context, symbol string) (result *SpotCancelOrderResponse, err error) {
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest("spot", http.MethodDelete, "/spot/order/batch-cancel", body, nil, true)
+	res, err := p.sendRequest(ctx, "spot", http.MethodDelete, "/spot/order/batch-cancel", body, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -139,10 +229,6 @@ func (p *Client) SpotCancelAllOrders(symbol string) (result *SpotCancelOrderResp
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -176,9 +262,13 @@ type SpotGetOrderResponse struct {
 }
 
 func (p *Client) SpotGetOrder(oid string) (result *SpotGetOrderResponse, err error) {
+	return p.SpotGetOrderContext(context.Background(), oid)
+}
+
+func (p *Client) SpotGetOrderContext(ctx context.Context, oid string) (result *SpotGetOrderResponse, err error) {
 	params := make(map[string]string)
 	params["orderId"] = oid
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/v1/order", nil, &params, true)
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/v1/order", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -190,46 +280,48 @@ func (p *Client) SpotGetOrder(oid string) (result *SpotGetOrderResponse, err err
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
+type SpotOrderData struct {
+	Accountid           string `json:"accountId"`
+	Exchangeid          string `json:"exchangeId"`
+	Symbol              string `json:"symbol"`
+	Symbolname          string `json:"symbolName"`
+	Orderlinkid         string `json:"orderLinkId"`
+	Orderid             string `json:"orderId"`
+	Price               string `json:"price"`
+	Origqty             string `json:"origQty"`
+	Executedqty         string `json:"executedQty"`
+	Cummulativequoteqty string `json:"cummulativeQuoteQty"`
+	Avgprice            string `json:"avgPrice"`
+	Status              string `json:"status"`
+	Timeinforce         string `json:"timeInForce"`
+	Type                string `json:"type"`
+	Side                string `json:"side"`
+	Stopprice           string `json:"stopPrice"`
+	Icebergqty          string `json:"icebergQty"`
+	Time                string `json:"time"`
+	Updatetime          string `json:"updateTime"`
+	Isworking           bool   `json:"isWorking"`
+}
+
 type SpotGetAllOrdersResponse struct {
-	RetCode int         `json:"ret_code"`
-	RetMsg  string      `json:"ret_msg"`
-	ExtCode interface{} `json:"ext_code"`
-	ExtInfo interface{} `json:"ext_info"`
-	Result  []struct {
-		Accountid           string `json:"accountId"`
-		Exchangeid          string `json:"exchangeId"`
-		Symbol              string `json:"symbol"`
-		Symbolname          string `json:"symbolName"`
-		Orderlinkid         string `json:"orderLinkId"`
-		Orderid             string `json:"orderId"`
-		Price               string `json:"price"`
-		Origqty             string `json:"origQty"`
-		Executedqty         string `json:"executedQty"`
-		Cummulativequoteqty string `json:"cummulativeQuoteQty"`
-		Avgprice            string `json:"avgPrice"`
-		Status              string `json:"status"`
-		Timeinforce         string `json:"timeInForce"`
-		Type                string `json:"type"`
-		Side                string `json:"side"`
-		Stopprice           string `json:"stopPrice"`
-		Icebergqty          string `json:"icebergQty"`
-		Time                string `json:"time"`
-		Updatetime          string `json:"updateTime"`
-		Isworking           bool   `json:"isWorking"`
-	} `json:"result"`
+	RetCode int             `json:"ret_code"`
+	RetMsg  string          `json:"ret_msg"`
+	ExtCode interface{}     `json:"ext_code"`
+	ExtInfo interface{}     `json:"ext_info"`
+	Result  []SpotOrderData `json:"result"`
 }
 
 func (p *Client) SpotGetAllOpenOrders(symbol string) (result *SpotGetAllOrdersResponse, err error) {
+	return p.SpotGetAllOpenOrdersContext(context.Background(), symbol)
+}
+
+func (p *Client) SpotGetAllOpenOrdersContext(ctx context.Context, symbol string) (result *SpotGetAllOrdersResponse, err error) {
 	params := make(map[string]string)
 	params["symbol"] = symbol
-	res, err := p.sendRequest("spot", http.MethodGet, "/spot/v1/open-orders", nil, &params, true)
+	res, err := p.sendRequest(ctx, "spot", http.MethodGet, "/spot/v1/open-orders", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -241,10 +333,6 @@ func (p *Client) SpotGetAllOpenOrders(symbol string) (result *SpotGetAllOrdersRe
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
 
@@ -259,12 +347,41 @@ type SpotBatchCancelOrdersResponse struct {
 	} `json:"result"`
 }
 
-// max 100 ids at once
+const spotBatchCancelLimit = 100
+
+// more than 100 ids are sent in chunks of 100, the results are merged.
+// if a chunk fails, the results of the chunks before it are returned with the error.
 func (p *Client) SpotBatchCancelOrdersByID(ids []string) (result *SpotBatchCancelOrdersResponse, err error) {
+	return p.SpotBatchCancelOrdersByIDContext(context.Background(), ids)
+}
+
+func (p *Client) SpotBatchCancelOrdersByIDContext(ctx context.Context, ids []string) (result *SpotBatchCancelOrdersResponse, err error) {
+	if len(ids) <= spotBatchCancelLimit {
+		return p.spotBatchCancelOrdersByID(ctx, ids)
+	}
+	for start := 0; start < len(ids); start += spotBatchCancelLimit {
+		end := start + spotBatchCancelLimit
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk, err := p.spotBatchCancelOrdersByID(ctx, ids[start:end])
+		if err != nil {
+			return result, err
+		}
+		if result == nil {
+			result = chunk
+			continue
+		}
+		result.Result = append(result.Result, chunk.Result...)
+	}
+	return result, nil
+}
+
+func (p *Client) spotBatchCancelOrdersByID(ctx context.Context, ids []string) (result *SpotBatchCancelOrdersResponse, err error) {
 	opts := strings.Join(ids, ",")
 	params := make(map[string]string)
 	params["orderIds"] = opts
-	res, err := p.sendRequest("spot", http.MethodDelete, "/spot/order/batch-cancel-by-ids", nil, &params, true)
+	res, err := p.sendRequest(ctx, "spot", http.MethodDelete, "/spot/order/batch-cancel-by-ids", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -276,9 +393,5 @@ func (p *Client) SpotBatchCancelOrdersByID(ids []string) (result *SpotBatchCance
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%d, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
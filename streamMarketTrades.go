@@ -12,6 +12,7 @@ import (
 
 type StreamMarketTradesBranch struct {
 	cancel       *context.CancelFunc
+	env          Environment
 	product      string
 	symbol       string
 	tradeChan    chan map[string]interface{}
@@ -32,9 +33,15 @@ type PublicTradeData struct {
 	Time    time.Time
 }
 
+// mainnet stream, use the Client method for other environments
 func StreamTradeSpot(symbol string, logger *logrus.Logger) *StreamMarketTradesBranch {
 	Usymbol := strings.ToUpper(symbol)
-	return streamTrade(ProductSpot, Usymbol, logger)
+	return streamTrade(Mainnet, ProductSpot, Usymbol, logger)
+}
+
+func (c *Client) StreamTradeSpot(symbol string, logger *logrus.Logger) *StreamMarketTradesBranch {
+	Usymbol := strings.ToUpper(symbol)
+	return streamTrade(c.env, ProductSpot, Usymbol, logger)
 }
 
 // side: Side of the taker in the trade
@@ -54,10 +61,11 @@ func (o *StreamMarketTradesBranch) Close() {
 }
 
 // spot only for now
-func streamTrade(product, symbol string, logger *logrus.Logger) *StreamMarketTradesBranch {
+func streamTrade(env Environment, product, symbol string, logger *logrus.Logger) *StreamMarketTradesBranch {
 	o := new(StreamMarketTradesBranch)
 	ctx, cancel := context.WithCancel(context.Background())
 	o.cancel = &cancel
+	o.env = env
 	o.product = product
 	o.symbol = symbol
 	o.tradeChan = make(chan map[string]interface{}, 100)
@@ -114,7 +122,7 @@ func (o *StreamMarketTradesBranch) maintainSession(ctx context.Context, errCh *c
 		case <-ctx.Done():
 			return
 		default:
-			if err := bybitSocket(ctx, o.product, o.symbol, "trade", o.logger, &o.tradeChan, errCh); err == nil {
+			if err := bybitSocket(ctx, o.env, o.product, o.symbol, "trade", o.logger, &o.tradeChan, errCh); err == nil {
 				return
 			} else {
 				o.logger.Warningf("reconnect Bybit %s trade stream with err: %s\n", o.symbol, err.Error())
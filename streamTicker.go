@@ -59,17 +59,27 @@ func (s *StreamTickerBranch) GetAsk() (price, qty string, timeStamp time.Time, o
 	return price, qty, timeStamp, true
 }
 
+// mainnet stream, use the Client method for other environments
 func StreamTickerSpot(symbol string, logger *log.Logger) *StreamTickerBranch {
-	return streamTicker(ProductSpot, symbol, logger)
+	return streamTicker(Mainnet, ProductSpot, symbol, logger)
 }
 
+// mainnet stream, use the Client method for other environments
 func StreamTickerPerp(symbol string, logger *log.Logger) *StreamTickerBranch {
-	return streamTicker(ProductPerp, symbol, logger)
+	return streamTicker(Mainnet, ProductPerp, symbol, logger)
+}
+
+func (c *Client) StreamTickerSpot(symbol string, logger *log.Logger) *StreamTickerBranch {
+	return streamTicker(c.env, ProductSpot, symbol, logger)
+}
+
+func (c *Client) StreamTickerPerp(symbol string, logger *log.Logger) *StreamTickerBranch {
+	return streamTicker(c.env, ProductPerp, symbol, logger)
 }
 
 // internal
 
-func streamTicker(product, symbol string, logger *log.Logger) *StreamTickerBranch {
+func streamTicker(env Environment, product, symbol string, logger *log.Logger) *StreamTickerBranch {
 	var s StreamTickerBranch
 	ctx, cancel := context.WithCancel(context.Background())
 	s.cancel = &cancel
@@ -81,7 +91,7 @@ func streamTicker(product, symbol string, logger *log.Logger) *StreamTickerBranc
 			case <-ctx.Done():
 				return
 			default:
-				if err := bybitSocket(ctx, product, symbol, "bookTicker", logger, &ticker, &errCh); err == nil {
+				if err := bybitSocket(ctx, env, product, symbol, "bookTicker", logger, &ticker, &errCh); err == nil {
 					return
 				} else {
 					logger.Warningf("Reconnect %s ticker stream with err: %s\n", symbol, err.Error())
@@ -0,0 +1,332 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// sub-member transfer direction, seen from the master account
+const (
+	SubTransferIn  = "IN"
+	SubTransferOut = "OUT"
+)
+
+type APIKey struct {
+	Key    string
+	Secret string
+}
+
+// KeyRegistry hands out the api key of a sub-account by name.
+type KeyRegistry interface {
+	Lookup(subaccount string) (APIKey, bool)
+}
+
+// sub-account name -> api key
+type StaticKeyRegistry map[string]APIKey
+
+func (r StaticKeyRegistry) Lookup(subaccount string) (APIKey, bool) {
+	key, ok := r[subaccount]
+	return key, ok
+}
+
+func WithKeyRegistry(keys KeyRegistry) ClientOption {
+	return func(c *Client) {
+		c.keys = keys
+	}
+}
+
+func (p *Client) Subaccount() string {
+	return p.subaccount
+}
+
+// ForSubaccount returns a client signing with the sub-account's key from the registry.
+// It shares the environment, http client, retry policy and server clock of p, but has its own
+// rate limiter, bybit counts the limits per account. The client is created once per name.
+func (p *Client) ForSubaccount(subaccount string) (*Client, error) {
+	if p.keys == nil {
+		return nil, errors.New("no key registry, see WithKeyRegistry")
+	}
+	p.subMux.Lock()
+	defer p.subMux.Unlock()
+	if c, ok := p.subClients[subaccount]; ok {
+		return c, nil
+	}
+	key, ok := p.keys.Lookup(subaccount)
+	if !ok {
+		return nil, errors.New("no api key for subaccount " + subaccount)
+	}
+	c := New(key.Key, key.Secret, subaccount)
+	c.client = p.client
+	c.env = p.env
+	c.retry = p.retry
+	c.clock = p.clock
+	c.recvWindow = p.recvWindow
+	c.batchConcurrency = p.batchConcurrency
+	if p.limiter == nil {
+		c.limiter = nil
+	} else {
+		c.limiter.mode = p.limiter.mode
+		c.limiter.maxWait = p.limiter.maxWait
+	}
+	if p.subClients == nil {
+		p.subClients = make(map[string]*Client)
+	}
+	p.subClients[subaccount] = c
+	return c, nil
+}
+
+type SubMemberIDsResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		SubMemberIDs             []string `json:"sub_member_ids"`
+		TransferableSubMemberIDs []string `json:"transferable_sub_member_ids"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// master account only
+func (p *Client) SubMemberIDs() (result *SubMemberIDsResponse, err error) {
+	return p.SubMemberIDsContext(context.Background())
+}
+
+func (p *Client) SubMemberIDsContext(ctx context.Context) (result *SubMemberIDsResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/sub-member/member-ids", nil, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type SubMemberTransferResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		TransferID string `json:"transfer_id"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// direction: SubTransferIn (master to sub) or SubTransferOut (sub to master), between the spot accounts.
+// transferID from NewTransferID, bybit won't execute it twice
+func (p *Client) SubMemberTransfer(transferID, coin, subUserID, direction string, amount decimal.Decimal) (result *SubMemberTransferResponse, err error) {
+	return p.SubMemberTransferContext(context.Background(), transferID, coin, subUserID, direction, amount)
+}
+
+func (p *Client) SubMemberTransferContext(ctx context.Context, transferID, coin, subUserID, direction string, amount decimal.Decimal) (result *SubMemberTransferResponse, err error) {
+	if transferID == "" {
+		return nil, errors.New("transfer id is required")
+	}
+	if direction != SubTransferIn && direction != SubTransferOut {
+		return nil, errors.New("direction must be IN or OUT")
+	}
+	params := make(map[string]string)
+	params["transfer_id"] = transferID
+	params["coin"] = coin
+	params["amount"] = amount.String()
+	params["sub_user_id"] = subUserID
+	params["type"] = direction
+	res, err := p.sendSignedBody(ctx, http.MethodPost, "/asset/v1/private/sub-member/transfer", params)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type SubMemberTransferRecord struct {
+	TransferID  string          `json:"transfer_id"`
+	Coin        string          `json:"coin"`
+	Amount      decimal.Decimal `json:"amount"`
+	UserID      string          `json:"user_id"`
+	SubMemberID string          `json:"sub_member_id"`
+	// seconds
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	// SubTransferIn / SubTransferOut
+	Type string `json:"type"`
+}
+
+type SubMemberTransferListResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		List   []SubMemberTransferRecord `json:"list"`
+		Cursor string                    `json:"cursor"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+// same filter as the internal transfers, one page
+func (p *Client) SubMemberTransferList(filter InternalTransferFilter) (result *SubMemberTransferListResponse, err error) {
+	return p.SubMemberTransferListContext(context.Background(), filter)
+}
+
+func (p *Client) SubMemberTransferListContext(ctx context.Context, filter InternalTransferFilter) (result *SubMemberTransferListResponse, err error) {
+	if filter.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params()
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/sub-member/transfer/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type UniversalTransferRequest struct {
+	// from NewTransferID, keep it to look the transfer up after a timeout
+	TransferID string
+	Coin       string
+	Amount     decimal.Decimal
+	// member ids, the master's or one of its sub-accounts
+	FromMemberID string
+	ToMemberID   string
+	// Contract, Spot, Investment
+	FromAccountType string
+	ToAccountType   string
+}
+
+func (r *UniversalTransferRequest) validate() error {
+	if r.TransferID == "" {
+		return errors.New("transfer id is required")
+	}
+	if r.Coin == "" {
+		return errors.New("coin is required")
+	}
+	if !r.Amount.IsPositive() {
+		return errors.New("amount must be positive")
+	}
+	if r.FromMemberID == "" || r.ToMemberID == "" {
+		return errors.New("from and to member ids are required")
+	}
+	if r.FromAccountType == "" || r.ToAccountType == "" {
+		return errors.New("from and to account types are required")
+	}
+	return nil
+}
+
+func (r *UniversalTransferRequest) params() map[string]string {
+	params := make(map[string]string)
+	params["transfer_id"] = r.TransferID
+	params["coin"] = strings.ToUpper(r.Coin)
+	params["amount"] = r.Amount.String()
+	params["from_member_id"] = r.FromMemberID
+	params["to_member_id"] = r.ToMemberID
+	params["from_account_type"] = r.FromAccountType
+	params["to_account_type"] = r.ToAccountType
+	return params
+}
+
+// between any two accounts of the master and its sub-accounts, master key only
+func (p *Client) UniversalTransfer(req UniversalTransferRequest) (result *SubMemberTransferResponse, err error) {
+	return p.UniversalTransferContext(context.Background(), req)
+}
+
+func (p *Client) UniversalTransferContext(ctx context.Context, req UniversalTransferRequest) (result *SubMemberTransferResponse, err error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	res, err := p.sendSignedBody(ctx, http.MethodPost, "/asset/v1/private/universal/transfer", req.params())
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+type UniversalTransferRecord struct {
+	TransferID      string          `json:"transfer_id"`
+	Coin            string          `json:"coin"`
+	Amount          decimal.Decimal `json:"amount"`
+	FromMemberID    string          `json:"from_member_id"`
+	ToMemberID      string          `json:"to_member_id"`
+	FromAccountType string          `json:"from_account_type"`
+	ToAccountType   string          `json:"to_account_type"`
+	// seconds
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+type UniversalTransferListResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		List   []UniversalTransferRecord `json:"list"`
+		Cursor string                    `json:"cursor"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) UniversalTransferList(filter InternalTransferFilter) (result *UniversalTransferListResponse, err error) {
+	return p.UniversalTransferListContext(context.Background(), filter)
+}
+
+func (p *Client) UniversalTransferListContext(ctx context.Context, filter InternalTransferFilter) (result *UniversalTransferListResponse, err error) {
+	if filter.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params()
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/universal/transfer/list", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package bybitapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const pathUniversalTransfer = "/asset/v1/private/universal/transfer"
+
+func TestUniversalTransferRequiresTransferID(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	req := bybitapi.UniversalTransferRequest{
+		Coin:            "usdt",
+		Amount:          decimal.NewFromInt(10),
+		FromMemberID:    "1",
+		ToMemberID:      "2",
+		FromAccountType: bybitapi.Contract,
+		ToAccountType:   bybitapi.Spot,
+	}
+	if _, err := client.UniversalTransfer(req); err == nil || err.Error() != "transfer id is required" {
+		t.Errorf("err = %v, want transfer id is required", err)
+	}
+	if _, ok := srv.LastRequest(http.MethodPost, pathUniversalTransfer); ok {
+		t.Error("a transfer without an id reached the server")
+	}
+
+	req.TransferID = bybitapi.NewTransferID()
+	srv.Respond(http.MethodPost, pathUniversalTransfer, bybittest.OKAsset(map[string]interface{}{"transfer_id": req.TransferID}))
+	if _, err := client.UniversalTransfer(req); err != nil {
+		t.Fatal(err)
+	}
+	wantParams(t, lastRequest(t, srv, http.MethodPost, pathUniversalTransfer), map[string]string{
+		"transfer_id": req.TransferID,
+		"coin":        "USDT",
+	})
+}
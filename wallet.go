@@ -1,11 +1,11 @@
 package bybitapi
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,32 +34,134 @@ type CreateInternalTransferResponse struct {
 }
 
 func (p *Client) CreateInternalTransfer(coin, from, to string, amount decimal.Decimal) (result *CreateInternalTransferResponse, err error) {
+	return p.CreateInternalTransferContext(context.Background(), coin, from, to, amount)
+}
+
+func (p *Client) CreateInternalTransferContext(ctx context.Context, coin, from, to string, amount decimal.Decimal) (result *CreateInternalTransferResponse, err error) {
+	return p.CreateInternalTransferWithIDContext(ctx, NewTransferID(), coin, from, to, amount)
+}
+
+// uuid, keep it before sending so the transfer can be looked up if the response is lost
+func NewTransferID() string {
+	return uuid.New().String()
+}
+
+// bybit won't execute the same transfer_id twice
+func (p *Client) CreateInternalTransferWithID(transferID, coin, from, to string, amount decimal.Decimal) (result *CreateInternalTransferResponse, err error) {
+	return p.CreateInternalTransferWithIDContext(context.Background(), transferID, coin, from, to, amount)
+}
+
+func (p *Client) CreateInternalTransferWithIDContext(ctx context.Context, transferID, coin, from, to string, amount decimal.Decimal) (result *CreateInternalTransferResponse, err error) {
+	if transferID == "" {
+		return nil, errors.New("transfer id is required")
+	}
 	params := make(map[string]string)
-	id := uuid.New()
-	params["transfer_id"] = id.String()
+	params["transfer_id"] = transferID
 	params["coin"] = coin
 	params["amount"] = amount.String()
 	params["from_account_type"] = from
 	params["to_account_type"] = to
-	q := url.Values{}
-	if params != nil {
-		for k, v := range params {
-			q.Add(k, v)
-		}
+	res, err := p.sendSignedBody(ctx, http.MethodPost, "/asset/v1/private/transfer", params)
+	if err != nil {
+		return nil, err
 	}
-	timestamp := time.Now().UnixNano() / 1e6
-	q.Add("api_key", p.key)
-	q.Add("timestamp", strconv.Itoa(int(timestamp)))
-	par := q.Encode()
-	signature := p.getSigned(par)
-	params["api_key"] = p.key
-	params["timestamp"] = strconv.Itoa(int(timestamp))
-	params["sign"] = signature
-	body, err := json.Marshal(params)
+	err = decode(res, &result)
 	if err != nil {
 		return nil, err
 	}
-	res, err := p.sendRequest(ProductPerp, http.MethodPost, "/asset/v1/private/transfer", body, nil, true)
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+const (
+	TransferStatusSuccess = "SUCCESS"
+	TransferStatusPending = "PENDING"
+	TransferStatusFailed  = "FAILED"
+)
+
+type InternalTransferFilter struct {
+	TransferID string
+	Coin       string
+	// TransferStatusSuccess, TransferStatusPending, TransferStatusFailed
+	Status    string
+	StartTime time.Time
+	EndTime   time.Time
+	// Prev / Next, paging from Cursor
+	Direction string
+	Cursor    string
+	// 20 when zero, max 50
+	Limit int
+}
+
+func (f *InternalTransferFilter) params() map[string]string {
+	params := make(map[string]string)
+	if f.TransferID != "" {
+		params["transfer_id"] = f.TransferID
+	}
+	if f.Coin != "" {
+		params["coin"] = strings.ToUpper(f.Coin)
+	}
+	if f.Status != "" {
+		params["status"] = f.Status
+	}
+	// seconds
+	if !f.StartTime.IsZero() {
+		params["start_time"] = strconv.FormatInt(f.StartTime.Unix(), 10)
+	}
+	if !f.EndTime.IsZero() {
+		params["end_time"] = strconv.FormatInt(f.EndTime.Unix(), 10)
+	}
+	if f.Direction != "" {
+		params["direction"] = f.Direction
+	}
+	if f.Cursor != "" {
+		params["cursor"] = f.Cursor
+	}
+	if f.Limit > 0 {
+		params["limit"] = strconv.Itoa(f.Limit)
+	}
+	return params
+}
+
+type InternalTransferRecord struct {
+	TransferID      string          `json:"transfer_id"`
+	Coin            string          `json:"coin"`
+	Amount          decimal.Decimal `json:"amount"`
+	FromAccountType string          `json:"from_account_type"`
+	ToAccountType   string          `json:"to_account_type"`
+	// seconds
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+type QueryInternalTransferListResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	Result  struct {
+		List []InternalTransferRecord `json:"list"`
+		// for the next page, with Direction Next
+		Cursor string `json:"cursor"`
+	} `json:"result"`
+	ExtInfo          interface{} `json:"ext_info"`
+	TimeNow          int64       `json:"time_now"`
+	RateLimitStatus  int         `json:"rate_limit_status"`
+	RateLimitResetMs int64       `json:"rate_limit_reset_ms"`
+	RateLimit        int         `json:"rate_limit"`
+}
+
+func (p *Client) QueryInternalTransferList(filter InternalTransferFilter) (result *QueryInternalTransferListResponse, err error) {
+	return p.QueryInternalTransferListContext(context.Background(), filter)
+}
+
+func (p *Client) QueryInternalTransferListContext(ctx context.Context, filter InternalTransferFilter) (result *QueryInternalTransferListResponse, err error) {
+	if filter.Limit > 50 {
+		return nil, errors.New("limit is at most 50")
+	}
+	params := filter.params()
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/asset/v1/private/transfer/list", nil, &params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +172,90 @@ func (p *Client) CreateInternalTransfer(coin, from, to string, amount decimal.De
 	if result == nil {
 		return nil, errors.New("response is nil")
 	}
-	if result.RetCode != 0 {
-		message := fmt.Sprintf("ret_code=%d, ret_msg=%s, ext_code=%s, ext_info=%s", result.RetCode, result.RetMsg, result.ExtCode, result.ExtInfo)
-		return nil, errors.New(message)
-	}
 	return result, nil
 }
+
+// nil record when bybit doesn't know the transfer id
+func (p *Client) LookupInternalTransfer(transferID string) (*InternalTransferRecord, error) {
+	return p.LookupInternalTransferContext(context.Background(), transferID)
+}
+
+func (p *Client) LookupInternalTransferContext(ctx context.Context, transferID string) (*InternalTransferRecord, error) {
+	result, err := p.QueryInternalTransferListContext(ctx, InternalTransferFilter{TransferID: transferID})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range result.Result.List {
+		if record.TransferID == transferID {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+const safeTransferAttempts = 3
+
+// SafeInternalTransfer moves funds at most once for transferID. Before every attempt the
+// transfer id is looked up, a known transfer is returned as is instead of being sent again,
+// so it's safe to call again with the same id after a timeout or a restart.
+// Only network errors and 5xx are retried, a rejected transfer returns the error.
+// A transfer listed as TransferStatusFailed returns its record with ErrTransferFailed.
+func (p *Client) SafeInternalTransfer(transferID, coin, from, to string, amount decimal.Decimal) (*InternalTransferRecord, error) {
+	return p.SafeInternalTransferContext(context.Background(), transferID, coin, from, to, amount)
+}
+
+func (p *Client) SafeInternalTransferContext(ctx context.Context, transferID, coin, from, to string, amount decimal.Decimal) (*InternalTransferRecord, error) {
+	if transferID == "" {
+		return nil, errors.New("transfer id is required")
+	}
+	var lastErr error
+	for attempt := 1; attempt <= safeTransferAttempts; attempt++ {
+		record, err := p.LookupInternalTransferContext(ctx, transferID)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			return transferOutcome(record)
+		}
+		_, err = p.CreateInternalTransferWithIDContext(ctx, transferID, coin, from, to, amount)
+		if err == nil {
+			record, err := p.LookupInternalTransferContext(ctx, transferID)
+			if err != nil || record == nil {
+				// accepted but not listed yet
+				return &InternalTransferRecord{
+					TransferID:      transferID,
+					Coin:            coin,
+					Amount:          amount,
+					FromAccountType: from,
+					ToAccountType:   to,
+					Status:          TransferStatusPending,
+				}, nil
+			}
+			return transferOutcome(record)
+		}
+		if !shouldRetry(ctx, err) {
+			return nil, err
+		}
+		lastErr = err
+		timer := time.NewTimer(time.Duration(attempt) * time.Second)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+	// the last attempt may still have gone through
+	record, err := p.LookupInternalTransferContext(ctx, transferID)
+	if err == nil && record != nil {
+		return transferOutcome(record)
+	}
+	return nil, lastErr
+}
+
+func transferOutcome(record *InternalTransferRecord) (*InternalTransferRecord, error) {
+	if record.Status == TransferStatusFailed {
+		return record, ErrTransferFailed
+	}
+	return record, nil
+}
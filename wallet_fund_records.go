@@ -0,0 +1,130 @@
+package bybitapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const walletFundRecordsMaxLimit = 50
+
+type PerpWalletFundRecordsFilter struct {
+	// every coin when empty
+	Coin string
+	// ex: WalletFundDeposit, WalletFundRealisedPNL, every type when empty.
+	// the endpoint takes one type, so each one is fetched on its own
+	Types []string
+	// only the dates are sent, in UTC
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+func (f *PerpWalletFundRecordsFilter) params() map[string]string {
+	params := make(map[string]string)
+	if f.Coin != "" {
+		params["coin"] = strings.ToUpper(f.Coin)
+	}
+	if !f.StartDate.IsZero() {
+		params["start_date"] = f.StartDate.UTC().Format("2006-01-02")
+	}
+	if !f.EndDate.IsZero() {
+		params["end_date"] = f.EndDate.UTC().Format("2006-01-02")
+	}
+	params["limit"] = strconv.Itoa(walletFundRecordsMaxLimit)
+	return params
+}
+
+type PerpWalletFundRecordData struct {
+	ID            int64           `json:"id"`
+	UserID        int64           `json:"user_id"`
+	Coin          string          `json:"coin"`
+	WalletID      int64           `json:"wallet_id"`
+	Type          string          `json:"type"`
+	Amount        decimal.Decimal `json:"amount"`
+	TxID          string          `json:"tx_id"`
+	Address       string          `json:"address"`
+	WalletBalance decimal.Decimal `json:"wallet_balance"`
+	ExecTime      time.Time       `json:"exec_time"`
+	CrossSeq      int64           `json:"cross_seq"`
+}
+
+type PerpWalletFundRecordsResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	ExtCode string `json:"ext_code"`
+	ExtInfo string `json:"ext_info"`
+	Result  struct {
+		Data []PerpWalletFundRecordData `json:"data"`
+	} `json:"result"`
+	TimeNow          string `json:"time_now"`
+	RateLimitStatus  int    `json:"rate_limit_status"`
+	RateLimitResetMs int64  `json:"rate_limit_reset_ms"`
+	RateLimit        int    `json:"rate_limit"`
+}
+
+// every page of every type in filter merged into one response, newest first
+func (p *Client) PerpWalletFundRecords(filter PerpWalletFundRecordsFilter) (result *PerpWalletFundRecordsResponse, err error) {
+	return p.PerpWalletFundRecordsContext(context.Background(), filter)
+}
+
+func (p *Client) PerpWalletFundRecordsContext(ctx context.Context, filter PerpWalletFundRecordsFilter) (result *PerpWalletFundRecordsResponse, err error) {
+	types := filter.Types
+	if len(types) == 0 {
+		types = []string{""}
+	}
+	for _, fundType := range types {
+		params := filter.params()
+		if fundType != "" {
+			params["wallet_fund_type"] = fundType
+		}
+		for page := 1; ; page++ {
+			params["page"] = strconv.Itoa(page)
+			pageResult, err := p.perpWalletFundRecords(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				result = pageResult
+			} else {
+				result.Result.Data = append(result.Result.Data, pageResult.Result.Data...)
+			}
+			if len(pageResult.Result.Data) < walletFundRecordsMaxLimit {
+				break
+			}
+		}
+	}
+	sort.SliceStable(result.Result.Data, func(i, j int) bool {
+		return result.Result.Data[i].ID > result.Result.Data[j].ID
+	})
+	return result, nil
+}
+
+func (p *Client) perpWalletFundRecords(ctx context.Context, params map[string]string) (result *PerpWalletFundRecordsResponse, err error) {
+	res, err := p.sendRequest(ctx, ProductPerp, http.MethodGet, "/v2/private/wallet/fund/records", nil, &params, true)
+	if err != nil {
+		return nil, err
+	}
+	err = decode(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errors.New("response is nil")
+	}
+	return result, nil
+}
+
+// sum of the signed amounts by type
+func (r *PerpWalletFundRecordsResponse) NetByType() map[string]decimal.Decimal {
+	out := make(map[string]decimal.Decimal)
+	for _, record := range r.Result.Data {
+		out[record.Type] = out[record.Type].Add(record.Amount)
+	}
+	return out
+}
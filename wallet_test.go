@@ -0,0 +1,139 @@
+package bybitapi_test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	bybitapi "github.com/dpong/Bybit_RESTapi"
+	"github.com/dpong/Bybit_RESTapi/bybittest"
+	"github.com/shopspring/decimal"
+)
+
+const pathInternalTransferList = "/asset/v1/private/transfer/list"
+
+// transfers bybit knows of, created ones are listed with status SUCCESS
+type transferBook struct {
+	mux     sync.Mutex
+	records map[string]map[string]interface{}
+}
+
+func newTransferBook(srv *bybittest.Server) *transferBook {
+	b := &transferBook{records: make(map[string]map[string]interface{})}
+	srv.Handle(http.MethodGet, pathInternalTransferList, func(r bybittest.Request) (int, interface{}) {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+		list := []map[string]interface{}{}
+		if record, ok := b.records[r.Param("transfer_id")]; ok {
+			list = append(list, record)
+		}
+		return http.StatusOK, bybittest.OKAsset(map[string]interface{}{"list": list, "cursor": ""})
+	})
+	srv.Handle(http.MethodPost, pathInternalTransfer, func(r bybittest.Request) (int, interface{}) {
+		b.add(r.Param("transfer_id"), bybitapi.TransferStatusSuccess)
+		return http.StatusOK, bybittest.OKAsset(map[string]interface{}{"transfer_id": r.Param("transfer_id")})
+	})
+	return b
+}
+
+func (b *transferBook) add(transferID, status string) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.records[transferID] = map[string]interface{}{
+		"transfer_id":       transferID,
+		"coin":              "USDT",
+		"amount":            "10",
+		"from_account_type": bybitapi.Contract,
+		"to_account_type":   bybitapi.Spot,
+		"timestamp":         "1650000000",
+		"status":            status,
+	}
+}
+
+func countRequests(srv *bybittest.Server, method, spath string) int {
+	n := 0
+	for _, req := range srv.Requests() {
+		if req.Method == method && req.Path == spath {
+			n++
+		}
+	}
+	return n
+}
+
+func safeTransfer(client *bybitapi.Client, transferID string) (*bybitapi.InternalTransferRecord, error) {
+	return client.SafeInternalTransfer(transferID, bybitapi.USDT, bybitapi.Contract, bybitapi.Spot, decimal.NewFromInt(10))
+}
+
+func TestSafeInternalTransferAlreadyDone(t *testing.T) {
+	srv, client := newTestClient(t)
+	newTransferBook(srv).add("t1", bybitapi.TransferStatusSuccess)
+
+	record, err := safeTransfer(client, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != bybitapi.TransferStatusSuccess {
+		t.Errorf("status = %q, want SUCCESS", record.Status)
+	}
+	if n := countRequests(srv, http.MethodPost, pathInternalTransfer); n != 0 {
+		t.Errorf("sent %d transfers, want none", n)
+	}
+}
+
+func TestSafeInternalTransferTimeoutListed(t *testing.T) {
+	srv, client := newTestClient(t, bybitapi.WithHTTPClient(&http.Client{Timeout: 200 * time.Millisecond}))
+	book := newTransferBook(srv)
+	// executed, but the response doesn't make it back in time
+	srv.Handle(http.MethodPost, pathInternalTransfer, func(r bybittest.Request) (int, interface{}) {
+		book.add(r.Param("transfer_id"), bybitapi.TransferStatusSuccess)
+		time.Sleep(500 * time.Millisecond)
+		return http.StatusOK, bybittest.OKAsset(map[string]interface{}{"transfer_id": r.Param("transfer_id")})
+	})
+
+	record, err := safeTransfer(client, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.TransferID != "t1" || record.Status != bybitapi.TransferStatusSuccess {
+		t.Errorf("record = %+v", record)
+	}
+	if n := countRequests(srv, http.MethodPost, pathInternalTransfer); n != 1 {
+		t.Errorf("sent %d transfers, want 1", n)
+	}
+}
+
+func TestSafeInternalTransferRejected(t *testing.T) {
+	srv, client := newTestClient(t)
+	newTransferBook(srv)
+	srv.Respond(http.MethodPost, pathInternalTransfer, bybittest.FailAsset(90001, "insufficient balance"))
+
+	record, err := safeTransfer(client, "t1")
+	var apiErr *bybitapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetCode != 90001 {
+		t.Errorf("err = %v, want ret_code 90001", err)
+	}
+	if record != nil {
+		t.Errorf("record = %+v, want nil", record)
+	}
+	if n := countRequests(srv, http.MethodPost, pathInternalTransfer); n != 1 {
+		t.Errorf("sent %d transfers, want 1", n)
+	}
+}
+
+func TestSafeInternalTransferFailedStatus(t *testing.T) {
+	srv, client := newTestClient(t)
+	newTransferBook(srv).add("t1", bybitapi.TransferStatusFailed)
+
+	record, err := safeTransfer(client, "t1")
+	if !errors.Is(err, bybitapi.ErrTransferFailed) {
+		t.Errorf("err = %v, want ErrTransferFailed", err)
+	}
+	if record == nil || record.Status != bybitapi.TransferStatusFailed {
+		t.Errorf("record = %+v, want the failed transfer", record)
+	}
+	if n := countRequests(srv, http.MethodPost, pathInternalTransfer); n != 0 {
+		t.Errorf("sent %d transfers, want none", n)
+	}
+}
@@ -86,8 +86,6 @@ func (w *ws) sendPingPong(product string) error {
 	if err := w.conn.WriteMessage(websocket.TextMessage, message); err != nil {
 		return err
 	}
-	
-
 
 	return nil
 }
@@ -136,6 +134,7 @@ func handleBybitSocketData(symobl string, res *map[string]interface{}, mainCh *c
 
 func bybitSocket(
 	ctx context.Context,
+	env Environment,
 	product, symbol string,
 	channel string,
 	logger *log.Logger,
@@ -150,13 +149,14 @@ func bybitSocket(
 	var url string
 	switch product {
 	case "perp":
-		url = "wss://stream.bybit.com/realtime_public"
+		url = env.streamURL("/realtime_public")
 	case "spot":
-		url = "wss://stream.bybit.com/spot/quote/ws/v2"
+		url = env.streamURL("/spot/quote/ws/v2")
 	}
 	// wait 5 second, if the hand shake fail, will terminate the dail
-	dailCtx, _ := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
+	dailCtx, dailCancel := context.WithDeadline(ctx, time.Now().Add(time.Second*5))
 	conn, _, err := websocket.DefaultDialer.DialContext(dailCtx, url, nil)
+	dailCancel()
 	if err != nil {
 		return err
 	}